@@ -0,0 +1,267 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	EnvToken      = "PREVIDER_TOKEN"
+	EnvTokenFile  = "PREVIDER_TOKEN_FILE"
+	EnvCustomer   = "PREVIDER_CUSTOMER"
+	EnvUrl        = "PREVIDER_URL"
+	EnvProfile    = "PREVIDER_PROFILE"
+	EnvConfigFile = "PREVIDER_CONFIG_FILE"
+
+	DefaultProfile = "default"
+)
+
+// ErrNoCredentials is returned by a CredentialProvider that has no credentials
+// available, so the next provider in a chain is tried
+var ErrNoCredentials = errors.New("no credentials found")
+
+type Credentials struct {
+	Token      string
+	CustomerId string
+	BaseUrl    string
+}
+
+// CredentialProvider supplies the credentials of a client. The token is retrieved
+// for every request, so implementations must be cheap and safe for concurrent use.
+type CredentialProvider interface {
+	Credentials(ctx context.Context) (*Credentials, error)
+}
+
+// DefaultCredentialProvider looks for credentials in the environment variables,
+// a token file given by PREVIDER_TOKEN_FILE and the profiles file, in that order
+func DefaultCredentialProvider() CredentialProvider {
+	return ChainCredentialProvider{
+		EnvCredentialProvider{},
+		&TokenFileCredentialProvider{},
+		&ProfileCredentialProvider{},
+	}
+}
+
+// ChainCredentialProvider returns the credentials of the first provider that has them
+type ChainCredentialProvider []CredentialProvider
+
+func (c ChainCredentialProvider) Credentials(ctx context.Context) (*Credentials, error) {
+	for _, provider := range c {
+		credentials, err := provider.Credentials(ctx)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		return credentials, err
+	}
+	return nil, ErrNoCredentials
+}
+
+// EnvCredentialProvider reads PREVIDER_TOKEN, PREVIDER_CUSTOMER and PREVIDER_URL
+type EnvCredentialProvider struct{}
+
+func (EnvCredentialProvider) Credentials(ctx context.Context) (*Credentials, error) {
+	token := os.Getenv(EnvToken)
+	if token == "" {
+		return nil, ErrNoCredentials
+	}
+	return &Credentials{Token: token, CustomerId: os.Getenv(EnvCustomer), BaseUrl: os.Getenv(EnvUrl)}, nil
+}
+
+// TokenFileCredentialProvider reads the token from a file, which is read again
+// whenever it changes, e.g. for a Kubernetes secret mounted as a volume. Like the
+// token, the environment variables are read for every request.
+type TokenFileCredentialProvider struct {
+	// Path defaults to PREVIDER_TOKEN_FILE
+	Path string
+	// CustomerId defaults to PREVIDER_CUSTOMER
+	CustomerId string
+	// BaseUrl defaults to PREVIDER_URL
+	BaseUrl string
+
+	file cachedFile
+}
+
+func (p *TokenFileCredentialProvider) Credentials(ctx context.Context) (*Credentials, error) {
+	path := valueOrEnv(p.Path, EnvTokenFile)
+	if path == "" {
+		return nil, ErrNoCredentials
+	}
+	token, err := p.file.read(path, func(content []byte) (interface{}, error) {
+		token := strings.TrimSpace(string(content))
+		if token == "" {
+			return nil, fmt.Errorf("token file %s is empty", path)
+		}
+		return token, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Credentials{Token: token.(string), CustomerId: valueOrEnv(p.CustomerId, EnvCustomer), BaseUrl: valueOrEnv(p.BaseUrl, EnvUrl)}, nil
+}
+
+// ProfileCredentialProvider reads a named profile from the profiles file, which
+// is read again whenever it changes. The file contains a section per profile:
+//
+//	[default]
+//	token = ...
+//	customer = ...
+//	url = https://portal.previder.com/api/
+//
+//	[staging]
+//	token_file = /var/run/secrets/previder/token
+//
+// A token_file is read like a TokenFileCredentialProvider.
+type ProfileCredentialProvider struct {
+	// Path defaults to PREVIDER_CONFIG_FILE or $XDG_CONFIG_HOME/previder/config, which defaults to ~/.config/previder/config
+	Path string
+	// Profile defaults to PREVIDER_PROFILE or default
+	Profile string
+
+	file      cachedFile
+	mu        sync.Mutex
+	tokenFile *TokenFileCredentialProvider
+}
+
+func (p *ProfileCredentialProvider) Credentials(ctx context.Context) (*Credentials, error) {
+	path, err := p.path()
+	if err != nil {
+		return nil, err
+	}
+	profileName := p.Profile
+	if profileName == "" {
+		profileName = os.Getenv(EnvProfile)
+	}
+	if profileName == "" {
+		profileName = DefaultProfile
+	}
+
+	profiles, err := p.file.read(path, func(content []byte) (interface{}, error) {
+		return parseProfiles(path, content)
+	})
+	if errors.Is(err, os.ErrNotExist) && p.Path == "" {
+		return nil, ErrNoCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	profile, ok := profiles.(map[string]map[string]string)[profileName]
+	if !ok {
+		if p.Profile == "" && os.Getenv(EnvProfile) == "" {
+			return nil, ErrNoCredentials
+		}
+		return nil, fmt.Errorf("profile %s not found in %s", profileName, path)
+	}
+
+	if profile["token_file"] != "" && profile["token"] == "" {
+		return p.tokenFileCredentials(ctx, profile)
+	}
+	if profile["token"] == "" {
+		return nil, fmt.Errorf("profile %s in %s has no token", profileName, path)
+	}
+	return &Credentials{Token: profile["token"], CustomerId: profile["customer"], BaseUrl: profile["url"]}, nil
+}
+
+func (p *ProfileCredentialProvider) tokenFileCredentials(ctx context.Context, profile map[string]string) (*Credentials, error) {
+	p.mu.Lock()
+	if p.tokenFile == nil || p.tokenFile.Path != profile["token_file"] {
+		p.tokenFile = &TokenFileCredentialProvider{Path: profile["token_file"]}
+	}
+	tokenFile := p.tokenFile
+	p.mu.Unlock()
+
+	credentials, err := tokenFile.Credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+	credentials.CustomerId = profile["customer"]
+	credentials.BaseUrl = profile["url"]
+	return credentials, nil
+}
+
+func (p *ProfileCredentialProvider) path() (string, error) {
+	if p.Path != "" {
+		return p.Path, nil
+	}
+	if path := os.Getenv(EnvConfigFile); path != "" {
+		return path, nil
+	}
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		return filepath.Join(configHome, "previder", "config"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "previder", "config"), nil
+}
+
+func valueOrEnv(value string, env string) string {
+	if value != "" {
+		return value
+	}
+	return os.Getenv(env)
+}
+
+func parseProfiles(path string, content []byte) (map[string]map[string]string, error) {
+	profiles := map[string]map[string]string{}
+	var profile map[string]string
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			profile = map[string]string{}
+			profiles[name] = profile
+		default:
+			key, value, ok := strings.Cut(line, "=")
+			if !ok || profile == nil {
+				return nil, fmt.Errorf("%s:%d: expected a [profile] or key = value", path, lineNumber)
+			}
+			profile[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+		}
+	}
+	return profiles, scanner.Err()
+}
+
+// cachedFile keeps the parsed content of a file until its modification time or size changes
+type cachedFile struct {
+	mu      sync.Mutex
+	path    string
+	modTime time.Time
+	size    int64
+	value   interface{}
+}
+
+func (f *cachedFile) read(path string, parse func(content []byte) (interface{}, error)) (interface{}, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.value != nil && f.path == path && f.modTime.Equal(info.ModTime()) && f.size == info.Size() {
+		return f.value, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	value, err := parse(content)
+	if err != nil {
+		return nil, err
+	}
+	f.path, f.modTime, f.size, f.value = path, info.ModTime(), info.Size(), value
+	return value, nil
+}
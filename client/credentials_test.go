@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseProfiles(t *testing.T) {
+	content := `
+# comment
+; comment
+[default]
+token = secret
+Customer = c1
+
+[ staging ]
+token_file = /run/token
+url = https://staging.example.com/api/
+`
+	profiles, err := parseProfiles("config", []byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]map[string]string{
+		"default": {"token": "secret", "customer": "c1"},
+		"staging": {"token_file": "/run/token", "url": "https://staging.example.com/api/"},
+	}
+	if len(profiles) != len(want) {
+		t.Fatalf("expected profiles %v, got %v", want, profiles)
+	}
+	for name, fields := range want {
+		for key, value := range fields {
+			if profiles[name][key] != value {
+				t.Errorf("expected %s.%s to be %q, got %q", name, key, value, profiles[name][key])
+			}
+		}
+	}
+
+	for _, invalid := range []string{"token = outside a profile", "[default]\nno separator"} {
+		if _, err := parseProfiles("config", []byte(invalid)); err == nil {
+			t.Errorf("expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestCachedFileReloadsOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	write := func(content string, modTime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var file cachedFile
+	parses := 0
+	read := func() string {
+		t.Helper()
+		value, err := file.read(path, func(content []byte) (interface{}, error) {
+			parses++
+			return string(content), nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return value.(string)
+	}
+
+	modTime := time.Now().Add(-time.Hour)
+	write("first", modTime)
+	if got := read(); got != "first" || parses != 1 {
+		t.Fatalf("expected first after 1 parse, got %s after %d", got, parses)
+	}
+	if got := read(); got != "first" || parses != 1 {
+		t.Fatalf("expected the cached value, got %s after %d parses", got, parses)
+	}
+	write("second", modTime)
+	if got := read(); got != "second" || parses != 2 {
+		t.Fatalf("expected a reload when the size changes, got %s after %d parses", got, parses)
+	}
+	write("third!", modTime.Add(time.Second))
+	if got := read(); got != "third!" || parses != 3 {
+		t.Fatalf("expected a reload when the modification time changes, got %s after %d parses", got, parses)
+	}
+}
+
+func TestDefaultCredentialProviderReadsEnvironmentPerCall(t *testing.T) {
+	t.Setenv(EnvToken, "")
+	t.Setenv(EnvTokenFile, "")
+	t.Setenv(EnvConfigFile, filepath.Join(t.TempDir(), "missing"))
+	provider := DefaultCredentialProvider()
+	if _, err := provider.Credentials(context.Background()); !errors.Is(err, ErrNoCredentials) {
+		t.Fatalf("expected ErrNoCredentials, got %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("file-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvTokenFile, path)
+	t.Setenv(EnvCustomer, "c1")
+	t.Setenv(EnvUrl, "https://example.com/api/")
+	credentials, err := provider.Credentials(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *credentials != (Credentials{Token: "file-token", CustomerId: "c1", BaseUrl: "https://example.com/api/"}) {
+		t.Errorf("expected the token file credentials, got %+v", credentials)
+	}
+}
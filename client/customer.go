@@ -1,13 +1,22 @@
 package client
 
-import "encoding/json"
+import (
+	"context"
+	"iter"
+)
 
 type CustomerService interface {
-	Page(request PageRequest) (*Page, *[]Customer, error)
+	Page(request PageRequest) (*Page[Customer], error)
+	PageWithContext(ctx context.Context, request PageRequest) (*Page[Customer], error)
+	All(ctx context.Context, request PageRequest) iter.Seq2[Customer, error]
 	Get(id string) (*CustomerExt, error)
+	GetWithContext(ctx context.Context, id string) (*CustomerExt, error)
 	Create(customerCreate CustomerCreate) (*Customer, error)
+	CreateWithContext(ctx context.Context, customerCreate CustomerCreate) (*Customer, error)
 	Delete(id string) error
+	DeleteWithContext(ctx context.Context, id string) error
 	Update(id string, customerUpdate CustomerCreate) (*Customer, error)
+	UpdateWithContext(ctx context.Context, id string, customerUpdate CustomerCreate) (*Customer, error)
 }
 
 type CustomerServiceImpl struct {
@@ -63,40 +72,55 @@ type CustomerCreate struct {
 	InvoiceToPartner    bool   `json:"invoiceToPartner,omitempty"`
 }
 
-func (c CustomerServiceImpl) Page(request PageRequest) (*Page, *[]Customer, error) {
-	page := new(Page)
-	err := c.client.Get(coreBasePath+"customer", page, &request)
-	if err != nil {
-		return nil, nil, err
-	}
+func (c CustomerServiceImpl) Page(request PageRequest) (*Page[Customer], error) {
+	return c.PageWithContext(context.Background(), request)
+}
 
-	customers := new([]Customer)
-	if err := json.Unmarshal(page.Content, &customers); err != nil {
-		return nil, nil, err
-	}
+func (c CustomerServiceImpl) PageWithContext(ctx context.Context, request PageRequest) (*Page[Customer], error) {
+	page := newPage[Customer](request)
+	err := c.client.GetWithContext(ctx, coreBasePath+"customer", page, &request)
+	return page, err
+}
 
-	return page, customers, err
+func (c CustomerServiceImpl) All(ctx context.Context, request PageRequest) iter.Seq2[Customer, error] {
+	return paginate(ctx, request, c.PageWithContext)
 }
 
 func (c CustomerServiceImpl) Get(id string) (*CustomerExt, error) {
+	return c.GetWithContext(context.Background(), id)
+}
+
+func (c CustomerServiceImpl) GetWithContext(ctx context.Context, id string) (*CustomerExt, error) {
 	customer := new(CustomerExt)
-	err := c.client.Get(coreBasePath+"customer/"+id, customer, nil)
+	err := c.client.GetWithContext(ctx, coreBasePath+"customer/"+id, customer, nil)
 	return customer, err
 }
 
 func (c CustomerServiceImpl) Create(customerCreate CustomerCreate) (*Customer, error) {
+	return c.CreateWithContext(context.Background(), customerCreate)
+}
+
+func (c CustomerServiceImpl) CreateWithContext(ctx context.Context, customerCreate CustomerCreate) (*Customer, error) {
 	customer := new(Customer)
-	err := c.client.Post(coreBasePath+"customer", customerCreate, customer)
+	err := c.client.PostWithContext(ctx, coreBasePath+"customer", customerCreate, customer)
 	return customer, err
 }
 
 func (c CustomerServiceImpl) Delete(id string) error {
-	err := c.client.Delete(coreBasePath+"customer/"+id, nil)
+	return c.DeleteWithContext(context.Background(), id)
+}
+
+func (c CustomerServiceImpl) DeleteWithContext(ctx context.Context, id string) error {
+	err := c.client.DeleteWithContext(ctx, coreBasePath+"customer/"+id, nil)
 	return err
 }
 
 func (c CustomerServiceImpl) Update(id string, customerUpdate CustomerCreate) (*Customer, error) {
+	return c.UpdateWithContext(context.Background(), id, customerUpdate)
+}
+
+func (c CustomerServiceImpl) UpdateWithContext(ctx context.Context, id string, customerUpdate CustomerCreate) (*Customer, error) {
 	customer := new(Customer)
-	err := c.client.Put(coreBasePath+id, customerUpdate, customer)
+	err := c.client.PutWithContext(ctx, coreBasePath+"customer/"+id, customerUpdate, customer)
 	return customer, err
 }
@@ -0,0 +1,26 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCustomerUpdatePath(t *testing.T) {
+	var method, path string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		_, _ = w.Write([]byte(`{"id":"c1","name":"Customer"}`))
+	}, nil)
+	customers := CustomerServiceImpl{client: c}
+
+	customer, err := customers.Update("c1", CustomerCreate{Name: "Customer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "/api/" + coreBasePath + "customer/c1"; method != http.MethodPut || path != want {
+		t.Errorf("expected PUT %s, got %s %s", want, method, path)
+	}
+	if customer.Id != "c1" {
+		t.Errorf("expected the updated customer, got %+v", customer)
+	}
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+const requestIdHeader = "X-Request-Id"
+
+// Sentinel errors to match an *ApiError against with errors.Is
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServer       = errors.New("server error")
+)
+
+// ApiError is returned for every response with a non 2xx status code
+type ApiError struct {
+	// Code is the HTTP status code of the response
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+	Method  string `json:"method,omitempty"`
+	Path    string `json:"path,omitempty"`
+	// ErrorCode is the error reported by the API, if the response body could be parsed
+	ErrorCode string `json:"error,omitempty"`
+	RequestId string `json:"requestId,omitempty"`
+	// Body is the raw response body
+	Body []byte `json:"-"`
+}
+
+type ApiErrorResponseBody struct {
+	Message string `json:"message,omitempty"`
+	Status  int    `json:"status,omitempty"`
+	Error   string `json:"error,omitempty"`
+	Path    string `json:"path,omitempty"`
+}
+
+func (e *ApiError) Error() string {
+	return fmt.Sprintf("%d - %s", e.Code, e.Message)
+}
+
+func (e *ApiError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.Code == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.Code == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.Code == http.StatusForbidden
+	case ErrNotFound:
+		return e.Code == http.StatusNotFound
+	case ErrConflict:
+		return e.Code == http.StatusConflict
+	case ErrRateLimited:
+		return e.Code == http.StatusTooManyRequests
+	case ErrServer:
+		return e.Code >= 500
+	}
+	return false
+}
+
+func newApiError(method string, path string, res *response) *ApiError {
+	apiError := &ApiError{
+		Code:      res.statusCode,
+		Method:    method,
+		Path:      path,
+		RequestId: res.header.Get(requestIdHeader),
+		Body:      res.body,
+	}
+
+	var apiErrorResponseBody ApiErrorResponseBody
+	if err := json.Unmarshal(res.body, &apiErrorResponseBody); err != nil {
+		// e.g. an HTML error page from a proxy in front of the API
+		apiError.Message = "Error while executing the request to " + path + ": " + http.StatusText(res.statusCode)
+		return apiError
+	}
+
+	if apiErrorResponseBody.Path != "" {
+		apiError.Path = apiErrorResponseBody.Path
+	}
+	apiError.ErrorCode = apiErrorResponseBody.Error
+	apiError.Message = "Error while executing the request to " + apiError.Path + ": " + apiErrorResponseBody.Message
+	if apiErrorResponseBody.Error != "" {
+		apiError.Message = apiError.Message + " - " + apiErrorResponseBody.Error
+	}
+	return apiError
+}
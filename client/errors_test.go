@@ -0,0 +1,58 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestApiErrorIs(t *testing.T) {
+	sentinels := []error{ErrBadRequest, ErrUnauthorized, ErrForbidden, ErrNotFound, ErrConflict, ErrRateLimited, ErrServer}
+	tests := []struct {
+		status int
+		want   error
+	}{
+		{http.StatusBadRequest, ErrBadRequest},
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusConflict, ErrConflict},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusInternalServerError, ErrServer},
+		{http.StatusBadGateway, ErrServer},
+		{http.StatusTeapot, nil},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			var err error = &ApiError{Code: tt.status}
+			for _, sentinel := range sentinels {
+				if got := errors.Is(err, sentinel); got != (sentinel == tt.want) {
+					t.Errorf("expected errors.Is(%v) to be %t", sentinel, !got)
+				}
+			}
+		})
+	}
+}
+
+func TestNewApiError(t *testing.T) {
+	res := &response{
+		statusCode: http.StatusConflict,
+		header:     http.Header{requestIdHeader: []string{"request-1"}},
+		body:       []byte(`{"message":"name is taken","status":409,"error":"Conflict","path":"/v2/iaas/virtualmachine"}`),
+	}
+	apiError := newApiError(http.MethodPost, "v2/iaas/virtualmachine", res)
+	if apiError.Code != http.StatusConflict || apiError.Method != http.MethodPost || apiError.RequestId != "request-1" ||
+		apiError.ErrorCode != "Conflict" || apiError.Path != "/v2/iaas/virtualmachine" || string(apiError.Body) != string(res.body) {
+		t.Errorf("unexpected ApiError %+v", apiError)
+	}
+	if !strings.Contains(apiError.Error(), "name is taken") {
+		t.Errorf("expected the message in %q", apiError.Error())
+	}
+
+	res = &response{statusCode: http.StatusBadGateway, header: http.Header{}, body: []byte("<html>Bad Gateway</html>")}
+	apiError = newApiError(http.MethodGet, "v2/iaas/virtualmachine", res)
+	if apiError.Code != http.StatusBadGateway || !errors.Is(apiError, ErrServer) || !strings.Contains(apiError.Message, "Bad Gateway") {
+		t.Errorf("expected the status of a non-JSON error response, got %+v", apiError)
+	}
+}
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// Interceptor hooks into every request attempt of a client, e.g. to add
+// headers, audit mutating calls, enforce policies or inject faults
+type Interceptor interface {
+	// BeforeRequest may modify the request before it is sent. Returning an
+	// error aborts the API call with that error, without retries.
+	BeforeRequest(ctx context.Context, req *http.Request, info RequestInfo) error
+	// AfterResponse sees the response, or the error when no response was received,
+	// and returns the result passed on to the next interceptor and the client. An
+	// interceptor that reads the response body must replace it. Returned errors
+	// are retried like transport errors, returning neither a response nor an
+	// error aborts the API call without retries.
+	AfterResponse(ctx context.Context, req *http.Request, info RequestInfo, res *http.Response, err error) (*http.Response, error)
+}
+
+// InterceptorFuncs implements Interceptor with optional functions
+type InterceptorFuncs struct {
+	Before func(ctx context.Context, req *http.Request, info RequestInfo) error
+	After  func(ctx context.Context, req *http.Request, info RequestInfo, res *http.Response, err error) (*http.Response, error)
+}
+
+func (f InterceptorFuncs) BeforeRequest(ctx context.Context, req *http.Request, info RequestInfo) error {
+	if f.Before == nil {
+		return nil
+	}
+	return f.Before(ctx, req, info)
+}
+
+func (f InterceptorFuncs) AfterResponse(ctx context.Context, req *http.Request, info RequestInfo, res *http.Response, err error) (*http.Response, error) {
+	if f.After == nil {
+		return res, err
+	}
+	return f.After(ctx, req, info, res, err)
+}
+
+var errNoResponse = errors.New("interceptor returned neither a response nor an error")
+
+// interceptorError marks an API call aborted by an interceptor
+type interceptorError struct {
+	err error
+}
+
+func (e *interceptorError) Error() string {
+	return e.err.Error()
+}
+
+func (e *interceptorError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// recordingInterceptor appends its name to the calls in both hooks
+func recordingInterceptor(name string, calls *[]string) Interceptor {
+	return InterceptorFuncs{
+		Before: func(ctx context.Context, req *http.Request, info RequestInfo) error {
+			*calls = append(*calls, "before "+name)
+			return nil
+		},
+		After: func(ctx context.Context, req *http.Request, info RequestInfo, res *http.Response, err error) (*http.Response, error) {
+			*calls = append(*calls, "after "+name)
+			return res, err
+		},
+	}
+}
+
+func TestInterceptorOrder(t *testing.T) {
+	var calls []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, "request "+r.Header.Get("X-Test"))
+	}, func(options *ClientOptions) {
+		options.Interceptors = []Interceptor{
+			recordingInterceptor("first", &calls),
+			InterceptorFuncs{Before: func(ctx context.Context, req *http.Request, info RequestInfo) error {
+				req.Header.Set("X-Test", "modified")
+				return nil
+			}},
+			recordingInterceptor("second", &calls),
+		}
+	})
+
+	if err := c.Get("version", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"before first", "before second", "request modified", "after second", "after first"}
+	if strings.Join(calls, ", ") != strings.Join(want, ", ") {
+		t.Errorf("expected %v, got %v", want, calls)
+	}
+}
+
+func TestInterceptorAbortsRequest(t *testing.T) {
+	var requests atomic.Int32
+	denied := errors.New("denied")
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}, func(options *ClientOptions) {
+		fastRetries(options)
+		options.Interceptors = []Interceptor{InterceptorFuncs{
+			Before: func(ctx context.Context, req *http.Request, info RequestInfo) error {
+				if req.Method == http.MethodDelete {
+					return denied
+				}
+				return nil
+			},
+		}}
+	})
+
+	if err := c.Delete("virtualmachine/1", nil); !errors.Is(err, denied) {
+		t.Fatalf("expected the interceptor error, got %v", err)
+	}
+	if got := requests.Load(); got != 0 {
+		t.Errorf("expected no requests to be sent, got %d", got)
+	}
+}
+
+func TestInterceptorReplacesResponse(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, func(options *ClientOptions) {
+		options.Interceptors = []Interceptor{InterceptorFuncs{
+			After: func(ctx context.Context, req *http.Request, info RequestInfo, res *http.Response, err error) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{},
+					Body:       io.NopCloser(strings.NewReader(`{"version":"replaced"}`)),
+				}, nil
+			},
+		}}
+	})
+
+	var version struct {
+		Version string `json:"version"`
+	}
+	if err := c.Get("version", &version, nil); err != nil {
+		t.Fatal(err)
+	}
+	if version.Version != "replaced" {
+		t.Errorf("expected the replaced response, got %+v", version)
+	}
+}
+
+func TestInterceptorWithoutResponse(t *testing.T) {
+	var requests atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}, func(options *ClientOptions) {
+		fastRetries(options)
+		options.Interceptors = []Interceptor{InterceptorFuncs{
+			After: func(ctx context.Context, req *http.Request, info RequestInfo, res *http.Response, err error) (*http.Response, error) {
+				return nil, nil
+			},
+		}}
+	})
+
+	if err := c.Get("version", nil, nil); !errors.Is(err, errNoResponse) {
+		t.Fatalf("expected errNoResponse, got %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("expected 1 attempt without retries, got %d", got)
+	}
+}
@@ -1,18 +1,31 @@
 package client
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"iter"
 )
 
 type KubernetesClusterService interface {
-	Page(request PageRequest) (*Page, *[]KubernetesCluster, error)
+	Page(request PageRequest) (*Page[KubernetesCluster], error)
+	PageWithContext(ctx context.Context, request PageRequest) (*Page[KubernetesCluster], error)
+	All(ctx context.Context, request PageRequest) iter.Seq2[KubernetesCluster, error]
 	Get(id string) (*KubernetesClusterExt, error)
-	Create(create KubernetesClusterCreate) (*Reference, error)
-	Delete(id string) error
-	Update(id string, update KubernetesClusterUpdate) error
+	GetWithContext(ctx context.Context, id string) (*KubernetesClusterExt, error)
+	Create(create KubernetesClusterCreate) (*Operation[*KubernetesClusterExt], error)
+	CreateWithContext(ctx context.Context, create KubernetesClusterCreate) (*Operation[*KubernetesClusterExt], error)
+	Delete(id string) (*Operation[*KubernetesClusterExt], error)
+	DeleteWithContext(ctx context.Context, id string) (*Operation[*KubernetesClusterExt], error)
+	Update(id string, update KubernetesClusterUpdate) (*Operation[*KubernetesClusterExt], error)
+	UpdateWithContext(ctx context.Context, id string, update KubernetesClusterUpdate) (*Operation[*KubernetesClusterExt], error)
 	GetKubeConfig(id string, endpoint string) (KubernetesClusterKubeConfigResponse, error)
+	GetKubeConfigWithContext(ctx context.Context, id string, endpoint string) (KubernetesClusterKubeConfigResponse, error)
 	GetNode(clusterId string, nodeName string) (*KubernetesClusterNodeInfo, error)
+	GetNodeWithContext(ctx context.Context, clusterId string, nodeName string) (*KubernetesClusterNodeInfo, error)
+	PageNodes(clusterId string, request PageRequest) (*Page[KubernetesClusterNodeInfo], error)
+	PageNodesWithContext(ctx context.Context, clusterId string, request PageRequest) (*Page[KubernetesClusterNodeInfo], error)
+	AllNodes(ctx context.Context, clusterId string, request PageRequest) iter.Seq2[KubernetesClusterNodeInfo, error]
+	WaitForState(ctx context.Context, id string, state string, options WaitOptions) (*KubernetesClusterExt, error)
 }
 
 type KubernetesClusterServiceImpl struct {
@@ -88,63 +101,113 @@ type KubernetesClusterNodeInfo struct {
 	DiscoveredAddresses []string `json:"discoveredAddresses"`
 }
 
-func (c *KubernetesClusterServiceImpl) Page(request PageRequest) (*Page, *[]KubernetesCluster, error) {
-	page := new(Page)
-	err := c.client.Get(kubernetesBasePath+"cluster", page, &request)
-	if err != nil {
-		return nil, nil, err
-	}
+func (c *KubernetesClusterServiceImpl) Page(request PageRequest) (*Page[KubernetesCluster], error) {
+	return c.PageWithContext(context.Background(), request)
+}
 
-	clusters := new([]KubernetesCluster)
-	if err := json.Unmarshal(page.Content, &clusters); err != nil {
-		return nil, nil, err
-	}
+func (c *KubernetesClusterServiceImpl) PageWithContext(ctx context.Context, request PageRequest) (*Page[KubernetesCluster], error) {
+	page := newPage[KubernetesCluster](request)
+	err := c.client.GetWithContext(ctx, kubernetesBasePath+"cluster", page, &request)
+	return page, err
+}
 
-	return page, clusters, err
+func (c *KubernetesClusterServiceImpl) All(ctx context.Context, request PageRequest) iter.Seq2[KubernetesCluster, error] {
+	return paginate(ctx, request, c.PageWithContext)
 }
 
 func (c *KubernetesClusterServiceImpl) Get(id string) (*KubernetesClusterExt, error) {
+	return c.GetWithContext(context.Background(), id)
+}
+
+func (c *KubernetesClusterServiceImpl) GetWithContext(ctx context.Context, id string) (*KubernetesClusterExt, error) {
 	cluster := new(KubernetesClusterExt)
-	err := c.client.Get(kubernetesBasePath+"cluster/"+id, cluster, nil)
+	err := c.client.GetWithContext(ctx, kubernetesBasePath+"cluster/"+id, cluster, nil)
 	return cluster, err
 }
 
-func (c *KubernetesClusterServiceImpl) Create(create KubernetesClusterCreate) (*Reference, error) {
+func (c *KubernetesClusterServiceImpl) Create(create KubernetesClusterCreate) (*Operation[*KubernetesClusterExt], error) {
+	return c.CreateWithContext(context.Background(), create)
+}
+
+func (c *KubernetesClusterServiceImpl) CreateWithContext(ctx context.Context, create KubernetesClusterCreate) (*Operation[*KubernetesClusterExt], error) {
 	response := new(Reference)
-	err := c.client.Post(kubernetesBasePath+"cluster", create, &response)
-	return response, err
+	err := c.client.PostWithContext(ctx, kubernetesBasePath+"cluster", create, &response)
+	if err != nil {
+		return nil, err
+	}
+	return newStateOperation(response.Id, c.WaitForState), nil
 }
 
-func (c *KubernetesClusterServiceImpl) Update(id string, update KubernetesClusterUpdate) error {
-	err := c.client.Put(kubernetesBasePath+"cluster/"+id, update, nil)
-	return err
+func (c *KubernetesClusterServiceImpl) Update(id string, update KubernetesClusterUpdate) (*Operation[*KubernetesClusterExt], error) {
+	return c.UpdateWithContext(context.Background(), id, update)
 }
 
-func (c *KubernetesClusterServiceImpl) Delete(id string) error {
-	err := c.client.Delete(kubernetesBasePath+"cluster/"+id, nil)
-	return err
+func (c *KubernetesClusterServiceImpl) UpdateWithContext(ctx context.Context, id string, update KubernetesClusterUpdate) (*Operation[*KubernetesClusterExt], error) {
+	err := c.client.PutWithContext(ctx, kubernetesBasePath+"cluster/"+id, update, nil)
+	if err != nil {
+		return nil, err
+	}
+	// without a modification time, the update is taken as applied once the cluster is ready
+	return newStateOperation(id, c.WaitForState), nil
+}
+
+func (c *KubernetesClusterServiceImpl) Delete(id string) (*Operation[*KubernetesClusterExt], error) {
+	return c.DeleteWithContext(context.Background(), id)
+}
+
+func (c *KubernetesClusterServiceImpl) DeleteWithContext(ctx context.Context, id string) (*Operation[*KubernetesClusterExt], error) {
+	err := c.client.DeleteWithContext(ctx, kubernetesBasePath+"cluster/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newDeleteOperation(id, c.GetWithContext), nil
 }
 
 func (c *KubernetesClusterServiceImpl) GetKubeConfig(id string, endpoint string) (KubernetesClusterKubeConfigResponse, error) {
+	return c.GetKubeConfigWithContext(context.Background(), id, endpoint)
+}
+
+func (c *KubernetesClusterServiceImpl) GetKubeConfigWithContext(ctx context.Context, id string, endpoint string) (KubernetesClusterKubeConfigResponse, error) {
 	requestKubeConfig := KubernetesClusterKubeConfigRequest{Endpoint: endpoint}
 	var response KubernetesClusterKubeConfigResponse
-	err := c.client.Post(kubernetesBasePath+"cluster/"+id+"/config", requestKubeConfig, &response)
+	err := c.client.PostWithContext(ctx, kubernetesBasePath+"cluster/"+id+"/config", requestKubeConfig, &response)
 	return response, err
 }
 
 func (c *KubernetesClusterServiceImpl) GetNode(clusterId string, nodeName string) (*KubernetesClusterNodeInfo, error) {
-	pageRequest := &PageRequest{Page: 0, Size: 1, Query: nodeName}
-	page := new(Page)
-	err := c.client.Get(kubernetesBasePath+"cluster/"+clusterId+"/nodes", &page, pageRequest)
+	return c.GetNodeWithContext(context.Background(), clusterId, nodeName)
+}
+
+func (c *KubernetesClusterServiceImpl) GetNodeWithContext(ctx context.Context, clusterId string, nodeName string) (*KubernetesClusterNodeInfo, error) {
+	page, err := c.PageNodesWithContext(ctx, clusterId, PageRequest{Page: 0, Size: 1, Query: nodeName})
 	if err != nil {
 		return nil, err
 	}
-	if page.NumberOfElements != 1 {
+	if page.NumberOfElements != 1 || len(page.Content) != 1 {
 		return nil, fmt.Errorf("expected number of elements 1, got %d", page.NumberOfElements)
 	}
-	var nodes []*KubernetesClusterNodeInfo
-	if err := json.Unmarshal(page.Content, &nodes); err != nil {
-		return nil, err
-	}
-	return nodes[0], err
+	return &page.Content[0], err
+}
+
+func (c *KubernetesClusterServiceImpl) PageNodes(clusterId string, request PageRequest) (*Page[KubernetesClusterNodeInfo], error) {
+	return c.PageNodesWithContext(context.Background(), clusterId, request)
+}
+
+func (c *KubernetesClusterServiceImpl) PageNodesWithContext(ctx context.Context, clusterId string, request PageRequest) (*Page[KubernetesClusterNodeInfo], error) {
+	page := newPage[KubernetesClusterNodeInfo](request)
+	err := c.client.GetWithContext(ctx, kubernetesBasePath+"cluster/"+clusterId+"/nodes", page, &request)
+	return page, err
+}
+
+func (c *KubernetesClusterServiceImpl) AllNodes(ctx context.Context, clusterId string, request PageRequest) iter.Seq2[KubernetesClusterNodeInfo, error] {
+	return paginate(ctx, request, func(ctx context.Context, request PageRequest) (*Page[KubernetesClusterNodeInfo], error) {
+		return c.PageNodesWithContext(ctx, clusterId, request)
+	})
+}
+
+// WaitForState polls the kubernetes cluster until it reaches the state or one of the failure states
+func (c *KubernetesClusterServiceImpl) WaitForState(ctx context.Context, id string, state string, options WaitOptions) (*KubernetesClusterExt, error) {
+	return waitForState(ctx, id, state, options, c.GetWithContext, func(cluster *KubernetesClusterExt) string {
+		return cluster.State
+	})
 }
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	redacted         = "REDACTED"
+	maxLoggedBodyLen = 4096
+)
+
+var redactedHeaders = []string{"X-Auth-Token", "Authorization", "Cookie", "Set-Cookie"}
+
+// redactedFields are JSON fields, compared case-insensitively, whose values never end up in the logging
+var redactedFields = map[string]bool{
+	"initialpassword": true,
+	"password":        true,
+	"token":           true,
+	"config":          true, // kubeconfig contents
+	"userdata":        true,
+}
+
+func (c *PreviderClient) logRequest(ctx context.Context, req *http.Request, requestBody []byte, res *response, attempt int, latency time.Duration, err error) {
+	if !c.clientOptions.Debug || !c.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+		slog.Int("attempt", attempt),
+		slog.Duration("latency", latency),
+		slog.Any("headers", redactHeaders(req.Header)),
+	}
+	if res != nil {
+		attrs = append(attrs, slog.Int("status", res.statusCode))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	if c.clientOptions.LogBodies {
+		attrs = append(attrs, slog.String("requestBody", redactBody(requestBody)))
+		if res != nil {
+			attrs = append(attrs, slog.String("responseBody", redactBody(res.body)))
+		}
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "Previder API request", attrs...)
+}
+
+func redactHeaders(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range redactedHeaders {
+		if header.Get(name) != "" {
+			header.Set(name, redacted)
+		}
+	}
+	return header
+}
+
+func redactBody(body []byte) string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		// not JSON, e.g. an HTML error page
+		if len(body) > maxLoggedBodyLen {
+			return string(body[:maxLoggedBodyLen]) + "..."
+		}
+		return string(body)
+	}
+	b, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return redacted
+	}
+	return string(b)
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if redactedFields[strings.ToLower(key)] {
+				v[key] = redacted
+			} else {
+				v[key] = redactValue(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
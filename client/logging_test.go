@@ -0,0 +1,88 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func newLoggingClient(t *testing.T, handler http.HandlerFunc, configure func(options *ClientOptions)) (*PreviderClient, *bytes.Buffer) {
+	t.Helper()
+	logs := new(bytes.Buffer)
+	c := newTestClient(t, handler, func(options *ClientOptions) {
+		options.Token = "secret-token"
+		options.Logger = slog.New(slog.NewJSONHandler(logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		options.RetryPolicy = &RetryPolicy{MaxAttempts: 1}
+		if configure != nil {
+			configure(options)
+		}
+	})
+	return c, logs
+}
+
+func TestDebugLoggingRedactsSecrets(t *testing.T) {
+	c, logs := newLoggingClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"config":"secret-kubeconfig","name":"cluster"}`))
+	}, func(options *ClientOptions) {
+		options.Debug = true
+		options.LogBodies = true
+	})
+
+	request := map[string]any{"name": "vm", "initialPassword": "secret-password", "nested": []any{map[string]any{"InitialPassword": "secret-nested"}}}
+	if err := c.Post("virtualmachine", request, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	output := logs.String()
+	for _, secret := range []string{"secret-token", "secret-password", "secret-nested", "secret-kubeconfig"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("expected %q to be redacted, got %s", secret, output)
+		}
+	}
+	for _, expected := range []string{`"X-Auth-Token":["REDACTED"]`, `\"initialPassword\":\"REDACTED\"`, `\"config\":\"REDACTED\"`, `\"name\":\"cluster\"`} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected %s in the logging, got %s", expected, output)
+		}
+	}
+}
+
+func TestLoggingWithoutDebug(t *testing.T) {
+	c, logs := newLoggingClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}, nil)
+
+	if err := c.Get("version", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("expected no logging for successful requests without Debug, got %s", logs)
+	}
+}
+
+func TestLoggingFailedRequests(t *testing.T) {
+	c, logs := newLoggingClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(requestIdHeader, "request-1")
+		w.WriteHeader(http.StatusNotFound)
+	}, nil)
+
+	if err := c.Get("virtualmachine/1", nil, nil); err == nil {
+		t.Fatal("expected an error")
+	}
+	output := logs.String()
+	if !strings.Contains(output, `"level":"WARN"`) || !strings.Contains(output, `"status":404`) || !strings.Contains(output, `"requestId":"request-1"`) {
+		t.Errorf("expected the error response at warn level, got %s", output)
+	}
+
+	c, logs = newLoggingClient(t, func(w http.ResponseWriter, r *http.Request) {}, func(options *ClientOptions) {
+		options.BaseUrl = "http://127.0.0.1:1"
+	})
+	if err := c.GetWithContext(context.Background(), "version", nil, nil); err == nil {
+		t.Fatal("expected an error")
+	}
+	if output := logs.String(); !strings.Contains(output, `"level":"ERROR"`) {
+		t.Errorf("expected the transport error at error level, got %s", output)
+	}
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"strconv"
+	"time"
+)
+
+// Metrics records the API calls of a client. Implementations must be safe for
+// concurrent use, see the prommetrics package for a Prometheus implementation.
+type Metrics interface {
+	// RequestStarted is called before the first attempt of an API call
+	RequestStarted(info RequestInfo)
+	// RequestRetried is called for every retry of an API call
+	RequestRetried(info RequestInfo)
+	// RequestFinished is called once the API call has completed, including all retries.
+	// StatusCode is 0 when no response was received.
+	RequestFinished(info RequestInfo, statusCode int, duration time.Duration, err error)
+}
+
+// StatusClass groups a result by its status code, e.g. 2xx or 5xx, or error when no response was received
+func StatusClass(statusCode int, err error) string {
+	if statusCode < 100 || statusCode > 599 {
+		if err != nil {
+			return "error"
+		}
+		return "unknown"
+	}
+	return strconv.Itoa(statusCode/100) + "xx"
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) RequestStarted(RequestInfo) {}
+
+func (noopMetrics) RequestRetried(RequestInfo) {}
+
+func (noopMetrics) RequestFinished(RequestInfo, int, time.Duration, error) {}
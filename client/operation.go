@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrOperationPending is returned by Operation.Result until the operation completed
+var ErrOperationPending = errors.New("operation has not completed")
+
+// Operation is a change to a resource that the platform applies asynchronously, tracked
+// by the task applying it or, for services without tasks, by polling the resource state.
+// Waiting for the latter requires the ready state of the resource in WaitOptions.ReadyState.
+//
+// An update of a virtual firewall is tracked by its modification time. As updates of
+// Kubernetes clusters and STaaS environments do not always change their state and the
+// resources have no modification time, their wait ends once the resource is in the
+// ready state, which may be observed before the update is applied.
+type Operation[T any] struct {
+	// ResourceId is the id of the created, updated or deleted resource
+	ResourceId string
+	// Task is the task applying the change, nil if the change is tracked by the resource state
+	Task *Task
+
+	wait func(ctx context.Context, options WaitOptions) (T, error)
+
+	mu     sync.Mutex
+	done   bool
+	result T
+	err    error
+}
+
+func newOperation[T any](resourceId string, task *Task, wait func(ctx context.Context, options WaitOptions) (T, error)) *Operation[T] {
+	return &Operation[T]{ResourceId: resourceId, Task: task, wait: wait}
+}
+
+// newTaskOperation waits for the task and then retrieves the result, if any
+func newTaskOperation[T any](tasks TaskService, resourceId string, task *Task, result func(ctx context.Context, id string) (T, error)) *Operation[T] {
+	return newOperation(resourceId, task, func(ctx context.Context, options WaitOptions) (T, error) {
+		var zero T
+		if _, err := tasks.WaitForWithOptions(ctx, task.Id, options); err != nil {
+			return zero, err
+		}
+		if result == nil {
+			return zero, nil
+		}
+		return result(ctx, resourceId)
+	})
+}
+
+// newStateOperation waits for the resource to reach the ready state of the wait options
+func newStateOperation[T any](resourceId string, waitForState func(ctx context.Context, id string, state string, options WaitOptions) (T, error)) *Operation[T] {
+	return newOperation(resourceId, nil, func(ctx context.Context, options WaitOptions) (T, error) {
+		if options.ReadyState == "" {
+			var zero T
+			return zero, ErrNoReadyState
+		}
+		return waitForState(ctx, resourceId, options.ReadyState, options)
+	})
+}
+
+// newUpdateOperation waits for modified to report the update as applied and, when the
+// wait options have a ready state, for the resource to be in that state
+func newUpdateOperation[T any](resourceId string, get func(ctx context.Context, id string) (T, error),
+	stateOf func(T) string, modified func(T) bool) *Operation[T] {
+	return newOperation(resourceId, nil, func(ctx context.Context, options WaitOptions) (T, error) {
+		return waitForUpdate(ctx, resourceId, options, get, stateOf, modified)
+	})
+}
+
+// newDeleteOperation waits until the resource is not found anymore
+func newDeleteOperation[T any](resourceId string, get func(ctx context.Context, id string) (T, error)) *Operation[T] {
+	return newOperation(resourceId, nil, func(ctx context.Context, options WaitOptions) (T, error) {
+		return waitForDeletion(ctx, resourceId, options, get)
+	})
+}
+
+// Done reports whether a wait observed the completion of the operation
+func (o *Operation[T]) Done() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.done
+}
+
+// Wait blocks until the operation completed, using DefaultWaitOptions, and returns its result
+func (o *Operation[T]) Wait(ctx context.Context) (T, error) {
+	return o.WaitWithOptions(ctx, DefaultWaitOptions)
+}
+
+func (o *Operation[T]) WaitWithOptions(ctx context.Context, options WaitOptions) (T, error) {
+	if result, err := o.Result(); !errors.Is(err, ErrOperationPending) {
+		return result, err
+	}
+
+	result, err := o.wait(ctx, options)
+	if err != nil && !completed(err) {
+		// the outcome is unknown, so a next wait polls again
+		return result, err
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.done, o.result, o.err = true, result, err
+	return result, err
+}
+
+// Result returns the resource after a create or update and the zero value after a delete,
+// or the error when the operation failed. It returns ErrOperationPending until Done.
+func (o *Operation[T]) Result() (T, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.done {
+		var zero T
+		return zero, ErrOperationPending
+	}
+	return o.result, o.err
+}
+
+// completed reports whether an error of a wait is the outcome of the operation
+func completed(err error) bool {
+	var taskFailed *TaskFailedError
+	var stateFailed *StateFailedError
+	return errors.As(err, &taskFailed) || errors.As(err, &stateFailed)
+}
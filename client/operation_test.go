@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUpdateOperation(t *testing.T) {
+	type resource struct {
+		state    string
+		modified int
+	}
+	tests := []struct {
+		name     string
+		firewall bool
+		// resources are returned by the polls, the first poll of a firewall precedes the update
+		resources []resource
+		// want is ready, failed or timeout
+		want  string
+		polls int32
+	}{
+		{"cluster ready on the first poll", false, []resource{{"READY", 0}}, "ready", 1},
+		{"cluster updating", false, []resource{{"UPDATING", 0}, {"UPDATING", 0}, {"READY", 0}}, "ready", 3},
+		{"cluster error", false, []resource{{"UPDATING", 0}, {"ERROR", 0}}, "failed", 2},
+		{"firewall modified on the first poll", true, []resource{{"READY", 1}, {"READY", 2}}, "ready", 2},
+		{"firewall modified later", true, []resource{{"READY", 1}, {"READY", 1}, {"UPDATING", 2}, {"READY", 2}}, "ready", 4},
+		{"firewall error", true, []resource{{"READY", 1}, {"ERROR", 2}}, "failed", 2},
+		{"firewall never modified", true, []resource{{"READY", 1}}, "timeout", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var polls atomic.Int32
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPut {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				poll := int(polls.Add(1)) - 1
+				resource := tt.resources[min(poll, len(tt.resources)-1)]
+				_, _ = w.Write([]byte(`{"id":"r1","state":"` + resource.state + `","audit":{"lastModifiedAt":` + strconv.Itoa(resource.modified) + `}}`))
+			}, nil)
+
+			options := WaitOptions{Timeout: 200 * time.Millisecond, InitialInterval: time.Millisecond, FailureStates: []string{"ERROR"}, ReadyState: "READY"}
+			var state string
+			var err error
+			var done bool
+			if tt.firewall {
+				firewalls := VirtualFirewallServiceImpl{client: c}
+				operation, updateErr := firewalls.Update("r1", VirtualFirewallUpdate{})
+				if updateErr != nil {
+					t.Fatal(updateErr)
+				}
+				firewall, waitErr := operation.WaitWithOptions(context.Background(), options)
+				state, err, done = firewall.State, waitErr, operation.Done()
+			} else {
+				clusters := KubernetesClusterServiceImpl{client: c}
+				operation, updateErr := clusters.Update("r1", KubernetesClusterUpdate{})
+				if updateErr != nil {
+					t.Fatal(updateErr)
+				}
+				cluster, waitErr := operation.WaitWithOptions(context.Background(), options)
+				state, err, done = cluster.State, waitErr, operation.Done()
+			}
+
+			var failed *StateFailedError
+			var timeout *StateTimeoutError
+			switch tt.want {
+			case "ready":
+				if err != nil || state != "READY" || !done {
+					t.Errorf("expected the ready resource, got %s, %v", state, err)
+				}
+			case "failed":
+				if !errors.As(err, &failed) || failed.State != "ERROR" || !done {
+					t.Errorf("expected a StateFailedError, got %v", err)
+				}
+			case "timeout":
+				if !errors.As(err, &timeout) || done {
+					t.Errorf("expected a StateTimeoutError for a pending operation, got %v", err)
+				}
+			}
+			if tt.polls > 0 && polls.Load() != tt.polls {
+				t.Errorf("expected %d polls, got %d", tt.polls, polls.Load())
+			}
+		})
+	}
+}
+
+func TestUpdateOperationWithoutReadyState(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"r1","state":"SOMETHING","audit":{"lastModifiedAt":1}}`))
+	}, nil)
+
+	clusters := KubernetesClusterServiceImpl{client: c}
+	cluster, err := clusters.Update("r1", KubernetesClusterUpdate{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cluster.Wait(context.Background()); !errors.Is(err, ErrNoReadyState) || cluster.Done() {
+		t.Errorf("expected ErrNoReadyState for a pending operation, got %v", err)
+	}
+
+	// a firewall update is tracked by its modification time alone
+	firewalls := VirtualFirewallServiceImpl{client: c}
+	firewall, err := firewalls.Update("r1", VirtualFirewallUpdate{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := WaitOptions{Timeout: 50 * time.Millisecond, InitialInterval: time.Millisecond}
+	var timeout *StateTimeoutError
+	if _, err := firewall.WaitWithOptions(context.Background(), options); !errors.As(err, &timeout) || timeout.Want != "updated" {
+		t.Errorf("expected a StateTimeoutError waiting for the update, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"net/url"
+	"strconv"
+)
+
+type SortDirection string
+
+const (
+	SortAscending  SortDirection = "asc"
+	SortDescending SortDirection = "desc"
+)
+
+type SortOrder struct {
+	Field     string
+	Direction SortDirection
+}
+
+func Ascending(field string) SortOrder {
+	return SortOrder{Field: field, Direction: SortAscending}
+}
+
+func Descending(field string) SortOrder {
+	return SortOrder{Field: field, Direction: SortDescending}
+}
+
+func (o SortOrder) String() string {
+	if o.Direction == "" {
+		return o.Field
+	}
+	return o.Field + "," + string(o.Direction)
+}
+
+// PageRequest selects a page of a paginated endpoint. Only the parameters that
+// are set are sent, zero values leave the defaults of the API in place.
+type PageRequest struct {
+	Page int
+	Size int
+	// Sort is sent as-is, before the SortOrders
+	Sort       string
+	SortOrders []SortOrder
+	// Query is a free-text search, e.g. the name of the resource. The API has no
+	// filter syntax, see Filter for conditions on the fields of the resources.
+	Query string
+}
+
+// NewPageRequest returns an empty PageRequest to build upon, e.g.
+//
+//	NewPageRequest().WithSize(50).SortBy(Ascending("name")).WithQuery("web")
+func NewPageRequest() PageRequest {
+	return PageRequest{}
+}
+
+func (r PageRequest) WithPage(page int) PageRequest {
+	r.Page = page
+	return r
+}
+
+func (r PageRequest) WithSize(size int) PageRequest {
+	r.Size = size
+	return r
+}
+
+// SortBy appends sort orders, earlier orders take precedence over later ones
+func (r PageRequest) SortBy(orders ...SortOrder) PageRequest {
+	r.SortOrders = append(r.SortOrders[:len(r.SortOrders):len(r.SortOrders)], orders...)
+	return r
+}
+
+func (r PageRequest) WithQuery(query string) PageRequest {
+	r.Query = query
+	return r
+}
+
+func (r PageRequest) values() url.Values {
+	q := url.Values{}
+	if r.Size > 0 {
+		q.Set("size", strconv.Itoa(r.Size))
+	}
+	if r.Page > 0 {
+		q.Set("page", strconv.Itoa(r.Page))
+	}
+	if r.Sort != "" {
+		q.Add("sort", r.Sort)
+	}
+	for _, order := range r.SortOrders {
+		if order.Field != "" {
+			q.Add("sort", order.String())
+		}
+	}
+	if r.Query != "" {
+		q.Set("query", r.Query)
+	}
+	return q
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"context"
+	"iter"
+)
+
+// paginate lazily fetches pages starting at request and yields their content until
+// the last page is reached, an error occurs or the caller stops iterating
+func paginate[T any](ctx context.Context, request PageRequest, fetch func(ctx context.Context, request PageRequest) (*Page[T], error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			page, err := fetch(ctx, request)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range page.Content {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if !page.HasNext() || len(page.Content) == 0 {
+				return
+			}
+			request = page.NextRequest()
+		}
+	}
+}
+
+// Filter yields the items for which match returns true, passing errors through. The API only
+// supports a free-text Query, so conditions on fields are evaluated on the client, e.g. the
+// virtual machines of a group that are powered off:
+//
+//	request := NewPageRequest().WithQuery(groupName)
+//	vms := Filter(c.VirtualServer.All(ctx, request), func(vm VirtualMachine) bool {
+//		return vm.GroupName == groupName && vm.State == VmStatePoweredOff
+//	})
+//
+// A Query narrowing down the items reduces the number of pages retrieved, as it is
+// evaluated by the API.
+func Filter[T any](items iter.Seq2[T, error], match func(T) bool) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for item, err := range items {
+			if err != nil || match(item) {
+				if !yield(item, err) {
+					return
+				}
+			}
+		}
+	}
+}
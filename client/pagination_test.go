@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	var queries []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("query"))
+		if r.URL.Query().Get("page") == "1" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"totalPages":2,"number":0,"content":[
+			{"id":"1","groupName":"web","state":"POWEREDOFF"},
+			{"id":"2","groupName":"web","state":"POWEREDON"},
+			{"id":"3","groupName":"webshop","state":"POWEREDOFF"}
+		]}`))
+	}, func(options *ClientOptions) {
+		options.RetryPolicy = &RetryPolicy{MaxAttempts: 1}
+	})
+	vms := VirtualServerServiceImpl{client: c}
+
+	var ids []string
+	var err error
+	poweredOff := Filter(vms.All(context.Background(), NewPageRequest().WithQuery("web")), func(vm VirtualMachine) bool {
+		return vm.GroupName == "web" && vm.State == VmStatePoweredOff
+	})
+	for vm, itemErr := range poweredOff {
+		if itemErr != nil {
+			err = itemErr
+			continue
+		}
+		ids = append(ids, vm.Id)
+	}
+	if len(ids) != 1 || ids[0] != "1" {
+		t.Errorf("expected the powered off virtual machine of the group, got %v", ids)
+	}
+	var apiError *ApiError
+	if !errors.As(err, &apiError) {
+		t.Errorf("expected the error of the second page, got %v", err)
+	}
+	if len(queries) != 2 || queries[0] != "web" {
+		t.Errorf("expected the query to be sent with every page, got %v", queries)
+	}
+}
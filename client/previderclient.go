@@ -2,13 +2,19 @@ package client
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
-	"strconv"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -24,6 +30,12 @@ const (
 type PreviderClient struct {
 	httpClient        *http.Client
 	clientOptions     *ClientOptions
+	logger            *slog.Logger
+	tracer            trace.Tracer
+	propagator        propagation.TextMapPropagator
+	metrics           Metrics
+	rateLimiter       *rateLimiter
+	inFlightLimiter   inFlightLimiter
 	Task              TaskService
 	VirtualServer     VirtualServerService
 	VirtualNetwork    VirtualNetworkService
@@ -38,48 +50,115 @@ type ApiInfo struct {
 	Name    string `json:"name,omitempty"`
 }
 
-type ApiError struct {
-	Code    int    `json:"code,omitempty"`
-	Message string `json:"message,omitempty"`
-}
-
-type ApiErrorResponseBody struct {
-	Message string `json:"message,omitempty"`
-	Status  int    `json:"status,omitempty"`
-	Error   string `json:"error,omitempty"`
-	Path    string `json:"path,omitempty"`
-}
-
 type ClientOptions struct {
 	Token      string
 	BaseUrl    string
 	CustomerId string
-}
 
-func (e *ApiError) Error() string {
-	return fmt.Sprintf("%d - %s", e.Code, e.Message)
+	// HttpClient is used as-is for all requests. It cannot be combined with
+	// Transport, ProxyUrl or TlsConfig.
+	HttpClient *http.Client
+	// Transport replaces the default transport. It cannot be combined with
+	// ProxyUrl or TlsConfig, configure those on the transport itself.
+	Transport http.RoundTripper
+	// ProxyUrl routes all requests through the given proxy, e.g. http://proxy.example.com:3128
+	ProxyUrl string
+	// TlsConfig is used for all connections, e.g. for custom CA bundles or client certificates
+	TlsConfig *tls.Config
+	// RequestTimeout limits the duration of a single request attempt, including reading the response body
+	RequestTimeout time.Duration
+	// RetryPolicy overrides DefaultRetryPolicy
+	RetryPolicy *RetryPolicy
+	// Logger receives requests that failed without a response at error level, error responses
+	// at warn level and, with Debug enabled, every request at debug level
+	Logger *slog.Logger
+	// Debug logs method, url, status and latency of every request
+	Debug bool
+	// LogBodies adds the redacted request and response bodies to the debug logging
+	LogBodies bool
+	// Credentials supplies the token for every request when Token is empty, and
+	// BaseUrl and CustomerId when those are empty, e.g. DefaultCredentialProvider()
+	Credentials CredentialProvider
+	// TracerProvider creates a span for every API call and task wait, defaults to the global OpenTelemetry provider
+	TracerProvider trace.TracerProvider
+	// Propagator injects the trace context into the request headers, defaults to the global OpenTelemetry propagator
+	Propagator propagation.TextMapPropagator
+	// Metrics records the count, duration, retries and status of all API calls
+	Metrics Metrics
+	// RateLimit limits the number of requests per second, shared by all services and customer views of the client
+	RateLimit float64
+	// RateBurst is the number of requests allowed at once before RateLimit applies, defaults to RateLimit rounded up
+	RateBurst int
+	// MaxInFlight limits the number of concurrent requests, shared by all services and customer views of the client
+	MaxInFlight int
+	// Interceptors see every request attempt before it is sent, in order, and every response, in reverse order
+	Interceptors []Interceptor
 }
 
 // noinspection GoUnusedExportedFunction
 func New(options *ClientOptions) (*PreviderClient, error) {
-	if options.Token == "" {
+	if options.Token == "" && options.Credentials == nil {
 		return nil, fmt.Errorf("missing token")
 	}
-	if options.BaseUrl == "" {
-		options.BaseUrl = defaultBaseURL
+	if options.RateLimit < 0 || options.RateBurst < 0 || options.MaxInFlight < 0 {
+		return nil, fmt.Errorf("rate limit, rate burst and max in flight cannot be negative")
 	}
-	if options.BaseUrl[len(options.BaseUrl)-1:] != "/" {
-		options.BaseUrl = options.BaseUrl + "/"
+	if options.Credentials != nil {
+		credentials, err := options.Credentials.Credentials(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve credentials: %w", err)
+		}
+		if options.BaseUrl == "" {
+			options.BaseUrl = credentials.BaseUrl
+		}
+		if options.CustomerId == "" {
+			options.CustomerId = credentials.CustomerId
+		}
 	}
-	if options.BaseUrl[len(options.BaseUrl)-4:] != "api/" {
-		options.BaseUrl = options.BaseUrl + "api/"
+	if options.CustomerId != "" {
+		if err := ValidateCustomerId(options.CustomerId); err != nil {
+			return nil, err
+		}
+	}
+	baseUrl, err := parseBaseUrl(options.BaseUrl)
+	if err != nil {
+		return nil, err
 	}
-	if options.BaseUrl[0:5] != "https" {
-		options.BaseUrl = "https://" + options.BaseUrl
+	options.BaseUrl = baseUrl
+
+	httpClient, err := newHttpClient(options)
+	if err != nil {
+		return nil, err
 	}
 
-	c := &PreviderClient{httpClient: http.DefaultClient, clientOptions: options}
+	logger := options.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	metrics := options.Metrics
+	if metrics == nil {
+		metrics = noopMetrics{}
+	}
+
+	c := &PreviderClient{
+		httpClient:    httpClient,
+		clientOptions: options,
+		logger:        logger,
+		tracer:        newTracer(options),
+		propagator:    newPropagator(options),
+		metrics:       metrics,
+	}
+	if options.RateLimit > 0 {
+		c.rateLimiter = newRateLimiter(options.RateLimit, options.RateBurst)
+	}
+	if options.MaxInFlight > 0 {
+		c.inFlightLimiter = make(inFlightLimiter, options.MaxInFlight)
+	}
+	c.initServices()
+	return c, nil
+}
 
+func (c *PreviderClient) initServices() {
 	c.Task = &TaskServiceOp{client: c}
 	c.VirtualServer = &VirtualServerServiceImpl{client: c}
 	c.VirtualNetwork = &VirtualNetworkServiceImpl{client: c}
@@ -87,108 +166,350 @@ func New(options *ClientOptions) (*PreviderClient, error) {
 	c.STaaSEnvironment = &STaaSEnvironmentServiceImpl{client: c}
 	c.VirtualFirewall = &VirtualFirewallServiceImpl{client: c}
 	c.Customer = &CustomerServiceImpl{client: c}
-	return c, nil
+}
+
+// WithCustomer returns a view of the client acting on behalf of another customer,
+// e.g. a sub-customer of a partner. The view shares the transport and all other
+// configuration with c, which itself is not modified, so it is safe to create
+// views concurrently.
+func (c *PreviderClient) WithCustomer(customerId string) (*PreviderClient, error) {
+	if err := ValidateCustomerId(customerId); err != nil {
+		return nil, err
+	}
+	options := *c.clientOptions
+	options.CustomerId = customerId
+
+	view := *c
+	view.clientOptions = &options
+	view.initServices()
+	return &view, nil
+}
+
+// CustomerId returns the customer the client acts on behalf of, or an empty string for the customer of the token
+func (c *PreviderClient) CustomerId() string {
+	return c.clientOptions.CustomerId
+}
+
+// ValidateCustomerId checks that id is a well-formed customer id of 24 hexadecimal characters
+func ValidateCustomerId(id string) error {
+	if len(id) != 24 {
+		return fmt.Errorf("invalid customer id %q: expected 24 characters, got %d", id, len(id))
+	}
+	for _, r := range id {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return fmt.Errorf("invalid customer id %q: expected hexadecimal characters only", id)
+		}
+	}
+	return nil
+}
+
+// parseBaseUrl normalises the base url of the API. Without a scheme https is
+// assumed, without a path /api/ is used and any other path gets a trailing slash.
+func parseBaseUrl(baseUrl string) (string, error) {
+	baseUrl = strings.TrimSpace(baseUrl)
+	if baseUrl == "" {
+		return defaultBaseURL, nil
+	}
+	if !strings.Contains(baseUrl, "://") {
+		baseUrl = "https://" + baseUrl
+	}
+
+	u, err := url.Parse(baseUrl)
+	if err != nil {
+		return "", fmt.Errorf("invalid base url: %w", err)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return "", fmt.Errorf("invalid base url %q: scheme must be http or https", baseUrl)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid base url %q: missing host", baseUrl)
+	}
+	if u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("invalid base url %q: user info, query and fragment are not supported", baseUrl)
+	}
+
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/api/"
+	} else if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	u.RawPath = ""
+	return u.String(), nil
+}
+
+func newHttpClient(options *ClientOptions) (*http.Client, error) {
+	if options.HttpClient != nil {
+		if options.Transport != nil || options.ProxyUrl != "" || options.TlsConfig != nil {
+			return nil, fmt.Errorf("HttpClient cannot be combined with Transport, ProxyUrl or TlsConfig")
+		}
+		return options.HttpClient, nil
+	}
+
+	if options.Transport != nil {
+		if options.ProxyUrl != "" || options.TlsConfig != nil {
+			return nil, fmt.Errorf("Transport cannot be combined with ProxyUrl or TlsConfig")
+		}
+		return &http.Client{Transport: options.Transport}, nil
+	}
+
+	if options.ProxyUrl == "" && options.TlsConfig == nil {
+		return http.DefaultClient, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if options.ProxyUrl != "" {
+		proxyUrl, err := url.Parse(options.ProxyUrl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url: %w", err)
+		}
+		if proxyUrl.Scheme == "" || proxyUrl.Host == "" {
+			return nil, fmt.Errorf("invalid proxy url %q: scheme and host are required", options.ProxyUrl)
+		}
+		transport.Proxy = http.ProxyURL(proxyUrl)
+	}
+	if options.TlsConfig != nil {
+		transport.TLSClientConfig = options.TlsConfig
+	}
+	return &http.Client{Transport: transport}, nil
 }
 
 func (c *PreviderClient) Get(url string, responseBody interface{}, requestParams *PageRequest) error {
-	return c.request("GET", url, nil, requestParams, &responseBody)
+	return c.GetWithContext(context.Background(), url, responseBody, requestParams)
 }
 
 func (c *PreviderClient) Delete(url string, responseBody interface{}) error {
-	return c.request("DELETE", url, &responseBody, nil, &responseBody)
+	return c.DeleteWithContext(context.Background(), url, responseBody)
 }
 
 func (c *PreviderClient) Post(url string, requestBody, responseBody interface{}) error {
-	return c.request("POST", url, &requestBody, nil, &responseBody)
+	return c.PostWithContext(context.Background(), url, requestBody, responseBody)
 }
 
 func (c *PreviderClient) Put(url string, requestBody, responseBody interface{}) error {
-	return c.request("PUT", url, &requestBody, nil, &responseBody)
+	return c.PutWithContext(context.Background(), url, requestBody, responseBody)
 }
 
-func (c *PreviderClient) request(method string, url string, requestBody interface{}, pageRequest *PageRequest, responseBody interface{}) error {
+func (c *PreviderClient) GetWithContext(ctx context.Context, url string, responseBody interface{}, requestParams *PageRequest) error {
+	return c.request(ctx, "GET", url, nil, requestParams, &responseBody)
+}
+
+func (c *PreviderClient) DeleteWithContext(ctx context.Context, url string, responseBody interface{}) error {
+	return c.request(ctx, "DELETE", url, &responseBody, nil, &responseBody)
+}
+
+func (c *PreviderClient) PostWithContext(ctx context.Context, url string, requestBody, responseBody interface{}) error {
+	return c.request(ctx, "POST", url, &requestBody, nil, &responseBody)
+}
+
+func (c *PreviderClient) PutWithContext(ctx context.Context, url string, requestBody, responseBody interface{}) error {
+	return c.request(ctx, "PUT", url, &requestBody, nil, &responseBody)
+}
+
+func (c *PreviderClient) request(ctx context.Context, method string, url string, requestBody interface{}, pageRequest *PageRequest, responseBody interface{}) (err error) {
+	info := describeRequest(method, url)
+	var res *response
+	retries := 0
+	ctx, span := c.startRequestSpan(ctx, method, url, info)
+	c.metrics.RequestStarted(info)
+	start := time.Now()
+	defer func() {
+		statusCode := 0
+		if res != nil {
+			statusCode = res.statusCode
+		}
+		c.metrics.RequestFinished(info, statusCode, time.Since(start), err)
+		endRequestSpan(span, res, retries, err)
+	}()
 
 	// content will be empty with GET, so can be sent anyway
 	b := new(bytes.Buffer)
-	err := json.NewEncoder(b).Encode(requestBody)
+	err = json.NewEncoder(b).Encode(requestBody)
+	if err != nil {
+		return err
+	}
+
+	token, err := c.token(ctx)
 	if err != nil {
 		return err
 	}
 
-	// Enable for POST/PUT debug
-	//fmt.Printf("%+v\n", b)
+	call := &apiCall{method: method, url: url, info: info, token: token, body: b.Bytes(), pageRequest: pageRequest}
+	retryPolicy := c.retryPolicy()
+	for attempt := 1; ; attempt++ {
+		retries = attempt - 1
+		res, err = c.send(ctx, call, attempt)
+		if attempt >= retryPolicy.MaxAttempts || !retryPolicy.appliesTo(method) || ctx.Err() != nil || !shouldRetry(res, err) {
+			break
+		}
 
-	req, err := http.NewRequest(method, c.clientOptions.BaseUrl+url, b)
+		delay := retryPolicy.backoff(attempt, res)
+		c.metrics.RequestRetried(info)
+		if retryPolicy.OnRetry != nil {
+			retryAttempt := RetryAttempt{Method: method, Url: c.clientOptions.BaseUrl + url, Attempt: attempt, Err: err, Delay: delay}
+			if res != nil {
+				retryAttempt.StatusCode = res.statusCode
+			}
+			retryPolicy.OnRetry(retryAttempt)
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
 	if err != nil {
+		c.logger.ErrorContext(ctx, "Previder API request failed", "method", method, "url", c.clientOptions.BaseUrl+url, "error", err)
 		return err
 	}
+
+	if res.statusCode < 200 || res.statusCode >= 300 {
+		apiError := newApiError(method, url, res)
+		c.logger.WarnContext(ctx, "Previder API request returned an error", "method", method, "url", c.clientOptions.BaseUrl+url,
+			"status", apiError.Code, "errorCode", apiError.ErrorCode, "requestId", apiError.RequestId, "error", apiError.Message)
+		return apiError
+	}
+
+	if responseBody != nil {
+		err := json.NewDecoder(bytes.NewReader(res.body)).Decode(&responseBody)
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *PreviderClient) token(ctx context.Context) (string, error) {
+	if c.clientOptions.Token != "" {
+		return c.clientOptions.Token, nil
+	}
+	credentials, err := c.clientOptions.Credentials.Credentials(ctx)
+	if err != nil {
+		return "", fmt.Errorf("could not retrieve credentials: %w", err)
+	}
+	if credentials.Token == "" {
+		return "", fmt.Errorf("missing token")
+	}
+	return credentials.Token, nil
+}
+
+// apiCall holds everything needed to send the attempts of a single API call
+type apiCall struct {
+	method      string
+	url         string
+	info        RequestInfo
+	token       string
+	body        []byte
+	pageRequest *PageRequest
+}
+
+type response struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+// send executes a single attempt of a request and reads the complete response body
+func (c *PreviderClient) send(ctx context.Context, call *apiCall, attempt int) (*response, error) {
+	release, err := c.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if c.clientOptions.RequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.clientOptions.RequestTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, call.method, c.clientOptions.BaseUrl+call.url, bytes.NewReader(call.body))
+	if err != nil {
+		return nil, err
+	}
 	req.Header.Set("Content-Type", jsonEncoding)
 
-	req.Header.Set("X-Auth-Token", c.clientOptions.Token)
+	req.Header.Set("X-Auth-Token", call.token)
 
 	req.Header.Set("Accept", jsonEncoding)
 
-	if c.clientOptions.CustomerId != "" && len(c.clientOptions.CustomerId) == 24 {
+	c.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	if c.clientOptions.CustomerId != "" {
 		req.Header.Set(customerHeader, c.clientOptions.CustomerId)
 	}
-	if pageRequest != nil {
-		q := req.URL.Query()
-		q.Add("size", strconv.Itoa(pageRequest.Size))
-		q.Add("page", strconv.Itoa(pageRequest.Page))
-		q.Add("sort", pageRequest.Sort)
-		q.Add("query", pageRequest.Query)
-		req.URL.RawQuery = q.Encode()
+	if call.pageRequest != nil {
+		req.URL.RawQuery = call.pageRequest.values().Encode()
 	}
-	res, requestErr := c.httpClient.Do(req)
-	if requestErr != nil {
-		log.Printf("[ERROR] [Previder API] Error from Previder API received")
-		return requestErr
+
+	for _, interceptor := range c.clientOptions.Interceptors {
+		if err := interceptor.BeforeRequest(ctx, req, call.info); err != nil {
+			return nil, &interceptorError{err: err}
+		}
 	}
 
+	start := time.Now()
+	res, err := c.do(req)
+	for i := len(c.clientOptions.Interceptors) - 1; i >= 0; i-- {
+		res, err = c.clientOptions.Interceptors[i].AfterResponse(ctx, req, call.info, res, err)
+	}
+	if res == nil && err == nil {
+		err = &interceptorError{err: errNoResponse}
+	}
+	if err != nil {
+		c.logRequest(ctx, req, call.body, nil, attempt, time.Since(start), err)
+		return nil, err
+	}
+
+	if res.Body == nil {
+		res.Body = http.NoBody
+	}
 	defer func() {
 		err := res.Body.Close()
 		if err != nil {
-			log.Fatal(err)
+			c.logger.WarnContext(ctx, "Could not close Previder API response body", "error", err)
 		}
 	}()
 
-	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		apiError := new(ApiError)
-
-		var apiErrorResponseBody ApiErrorResponseBody
-		temp, err := io.ReadAll(res.Body)
-		err = json.Unmarshal(temp, &apiErrorResponseBody)
-
-		var tmpBuffer []byte
-		json.Unmarshal(temp, &tmpBuffer)
-
-		if err != nil {
-			return errors.New(fmt.Sprintf("could not parse error result: %v", err.Error()))
-		}
-
-		apiError.Code = res.StatusCode
-		apiError.Message = "Error while executing the request to " + apiErrorResponseBody.Path + ": " + apiErrorResponseBody.Message
-		if apiErrorResponseBody.Error != "" {
-			apiError.Message = apiError.Message + " - " + apiErrorResponseBody.Error
-		}
-		return apiError
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		c.logRequest(ctx, req, call.body, nil, attempt, time.Since(start), err)
+		return nil, err
 	}
+	r := &response{statusCode: res.StatusCode, header: res.Header, body: resBody}
+	c.logRequest(ctx, req, call.body, r, attempt, time.Since(start), nil)
+	return r, nil
+}
 
-	if responseBody != nil {
-		err := json.NewDecoder(res.Body).Decode(&responseBody)
+// do sends the request and buffers the response body, so the connection is
+// released before the interceptors get to see the response
+func (c *PreviderClient) do(req *http.Request) (*http.Response, error) {
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err := res.Body.Close()
 		if err != nil {
-			if err == io.EOF {
-				return nil
-			}
-			return err
+			c.logger.WarnContext(req.Context(), "Could not close Previder API response body", "error", err)
 		}
-	}
+	}()
 
-	return nil
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	return res, nil
 }
 
 func (c *PreviderClient) ApiInfo() (*ApiInfo, error) {
+	return c.ApiInfoWithContext(context.Background())
+}
+
+func (c *PreviderClient) ApiInfoWithContext(ctx context.Context) (*ApiInfo, error) {
 	apiInfo := new(ApiInfo)
-	err := c.Get("", apiInfo, nil)
+	err := c.GetWithContext(ctx, "", apiInfo, nil)
 	return apiInfo, err
 }
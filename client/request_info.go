@@ -0,0 +1,56 @@
+package client
+
+import "strings"
+
+// RequestInfo describes an API call by service and operation, so it can be
+// reported without the unbounded cardinality of raw urls
+type RequestInfo struct {
+	// Service is one of iaas, kubernetes, staas or core
+	Service string
+	// Operation is the method and path template, e.g. GET virtualmachine/{id}
+	Operation string
+	// ResourceId is the id of the resource addressed by the call, if any
+	ResourceId string
+}
+
+var servicePaths = []struct {
+	service  string
+	basePath string
+}{
+	{"iaas", iaasBasePath},
+	{"kubernetes", kubernetesBasePath},
+	{"staas", staasBasePath},
+	{"core", coreBasePath},
+}
+
+func describeRequest(method string, path string) RequestInfo {
+	info := RequestInfo{Service: "api"}
+	for _, servicePath := range servicePaths {
+		if strings.HasPrefix(path, servicePath.basePath) {
+			info.Service = servicePath.service
+			path = strings.TrimPrefix(path, servicePath.basePath)
+			break
+		}
+	}
+
+	// paths alternate between collections and ids, e.g. virtualfirewall/{id}/natrules/{id},
+	// except for the actions of virtual machines which are kept as-is
+	var template []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" {
+			continue
+		}
+		if len(template)%2 == 1 && template[len(template)-1] != "action" {
+			if info.ResourceId == "" {
+				info.ResourceId = segment
+			}
+			segment = "{id}"
+		}
+		template = append(template, segment)
+	}
+	info.Operation = method + " " + strings.Join(template, "/")
+	if len(template) == 0 {
+		info.Operation = method + " /"
+	}
+	return info
+}
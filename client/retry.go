@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultRetryPolicy is used when ClientOptions.RetryPolicy is not set
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     30 * time.Second,
+}
+
+// RetryPolicy controls how failed requests are retried. Transport errors and
+// 429, 500, 502, 503 and 504 responses are retried for GET, PUT and DELETE
+// requests, and for POST requests only when RetryPost is set.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one. A value of 1 disables retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry, doubled on every subsequent retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the computed delay between attempts. A Retry-After header sent by the server takes precedence.
+	MaxBackoff time.Duration
+	// RetryPost enables retries for non-idempotent POST requests
+	RetryPost bool
+	// OnRetry is called before waiting for the next attempt
+	OnRetry func(attempt RetryAttempt)
+}
+
+// RetryAttempt describes a failed attempt that is about to be retried
+type RetryAttempt struct {
+	Method string
+	Url    string
+	// Attempt is the number of the failed attempt, starting at 1
+	Attempt int
+	// StatusCode is the status of the failed attempt, or 0 when the request itself failed
+	StatusCode int
+	Err        error
+	Delay      time.Duration
+}
+
+func (c *PreviderClient) retryPolicy() RetryPolicy {
+	if c.clientOptions.RetryPolicy == nil {
+		return DefaultRetryPolicy
+	}
+	return *c.clientOptions.RetryPolicy
+}
+
+func (p RetryPolicy) appliesTo(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		return p.RetryPost
+	}
+	return false
+}
+
+func (p RetryPolicy) backoff(attempt int, res *response) time.Duration {
+	delay := p.InitialBackoff
+	for i := 1; i < attempt && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if delay > 0 {
+		// equal jitter, so concurrent clients do not retry in lockstep
+		delay = delay/2 + rand.N(delay/2+1)
+	}
+
+	if res != nil {
+		if retryAfter, ok := parseRetryAfter(res.header.Get("Retry-After")); ok && retryAfter > delay {
+			delay = retryAfter
+		}
+	}
+	return delay
+}
+
+// shouldRetry decides on the outcome of a single attempt. Errors of the attempt,
+// including its RequestTimeout, are retried; the caller checks its own context.
+func shouldRetry(res *response, err error) bool {
+	if err != nil {
+		var aborted *interceptorError
+		return !errors.As(err, &aborted)
+	}
+	return retryableStatus(res.statusCode)
+}
+
+func retryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,205 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client for a plain HTTP test server running the handler
+func newTestClient(t *testing.T, handler http.HandlerFunc, configure func(options *ClientOptions)) *PreviderClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	options := &ClientOptions{Token: "test-token", BaseUrl: server.URL}
+	if configure != nil {
+		configure(options)
+	}
+	c, err := New(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// hang blocks until the client gave up on the request. The body is read first,
+// as the server only notices a closed connection once the body was consumed.
+func hang(r *http.Request) {
+	_, _ = io.Copy(io.Discard, r.Body)
+	<-r.Context().Done()
+}
+
+// fastRetries retries without noticeable delays
+func fastRetries(options *ClientOptions) {
+	options.RetryPolicy = &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+}
+
+func TestRetryTimedOutAttempt(t *testing.T) {
+	var attempts atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			hang(r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"version":"1"}`))
+	}, func(options *ClientOptions) {
+		fastRetries(options)
+		options.RequestTimeout = 100 * time.Millisecond
+	})
+
+	if err := c.Get("version", nil, nil); err != nil {
+		t.Fatalf("expected the timed out attempt to be retried, got %v", err)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("expected 2 attempts, got %d", got)
+	}
+}
+
+func TestRetryStopsWhenCallerContextIsDone(t *testing.T) {
+	var attempts atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		hang(r)
+	}, fastRetries)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := c.GetWithContext(ctx, "version", nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("expected 1 attempt, got %d", got)
+	}
+}
+
+func TestRetryStatus(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		retryPost    bool
+		status       int
+		wantAttempts int32
+	}{
+		{"get 429", http.MethodGet, false, http.StatusTooManyRequests, 2},
+		{"put 503", http.MethodPut, false, http.StatusServiceUnavailable, 2},
+		{"delete 502", http.MethodDelete, false, http.StatusBadGateway, 2},
+		{"get 404", http.MethodGet, false, http.StatusNotFound, 1},
+		{"post 503", http.MethodPost, false, http.StatusServiceUnavailable, 1},
+		{"post 503 with RetryPost", http.MethodPost, true, http.StatusServiceUnavailable, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if attempts.Add(1) == 1 {
+					w.WriteHeader(tt.status)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}, func(options *ClientOptions) {
+				fastRetries(options)
+				options.RetryPolicy.RetryPost = tt.retryPost
+			})
+
+			err := c.request(context.Background(), tt.method, "version", nil, nil, nil)
+			if tt.wantAttempts == 1 {
+				var apiError *ApiError
+				if !errors.As(err, &apiError) || apiError.Code != tt.status {
+					t.Errorf("expected an ApiError with status %d, got %v", tt.status, err)
+				}
+			} else if err != nil {
+				t.Errorf("expected success after a retry, got %v", err)
+			}
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("expected %d attempts, got %d", tt.wantAttempts, got)
+			}
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	var attempts atomic.Int32
+	var retries []RetryAttempt
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}, func(options *ClientOptions) {
+		fastRetries(options)
+		options.RetryPolicy.OnRetry = func(attempt RetryAttempt) {
+			retries = append(retries, attempt)
+		}
+	})
+
+	start := time.Now()
+	if err := c.Get("version", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected to wait for Retry-After, retried after %s", elapsed)
+	}
+	if len(retries) != 1 {
+		t.Fatalf("expected OnRetry to be called once, got %d", len(retries))
+	}
+	retry := retries[0]
+	if retry.Method != http.MethodGet || retry.Attempt != 1 || retry.StatusCode != http.StatusTooManyRequests ||
+		retry.Delay != time.Second || retry.Url != c.clientOptions.BaseUrl+"version" {
+		t.Errorf("unexpected retry attempt %+v", retry)
+	}
+}
+
+func TestRetryOnRetryHookForTransportErrors(t *testing.T) {
+	var retries []RetryAttempt
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		hang(r)
+	}, func(options *ClientOptions) {
+		fastRetries(options)
+		options.RequestTimeout = 20 * time.Millisecond
+		options.RetryPolicy.OnRetry = func(attempt RetryAttempt) {
+			retries = append(retries, attempt)
+		}
+	})
+
+	err := c.Get("version", nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the last attempt to time out, got %v", err)
+	}
+	if len(retries) != 2 {
+		t.Fatalf("expected 2 retries, got %d", len(retries))
+	}
+	for i, retry := range retries {
+		if retry.Attempt != i+1 || retry.StatusCode != 0 || !errors.Is(retry.Err, context.DeadlineExceeded) {
+			t.Errorf("unexpected retry attempt %+v", retry)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for attempt := 1; attempt <= 6; attempt++ {
+		delay := policy.backoff(attempt, nil)
+		limit := min(policy.InitialBackoff<<(attempt-1), policy.MaxBackoff)
+		if delay < limit/2 || delay > limit {
+			t.Errorf("attempt %d: expected a delay between %s and %s, got %s", attempt, limit/2, limit, delay)
+		}
+	}
+
+	res := &response{header: http.Header{"Retry-After": []string{"120"}}}
+	if delay := policy.backoff(1, res); delay != 2*time.Minute {
+		t.Errorf("expected Retry-After to take precedence over MaxBackoff, got %s", delay)
+	}
+	res.header.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	if delay := policy.backoff(1, res); delay < 59*time.Minute {
+		t.Errorf("expected a Retry-After date an hour from now, got %s", delay)
+	}
+}
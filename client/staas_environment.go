@@ -1,18 +1,33 @@
 package client
 
-import "encoding/json"
+import (
+	"context"
+	"iter"
+)
 
 type STaaSEnvironmentService interface {
-	Page(request PageRequest) (*Page, *[]STaaSEnvironment, error)
+	Page(request PageRequest) (*Page[STaaSEnvironment], error)
+	PageWithContext(ctx context.Context, request PageRequest) (*Page[STaaSEnvironment], error)
+	All(ctx context.Context, request PageRequest) iter.Seq2[STaaSEnvironment, error]
 	Get(id string) (*STaaSEnvironmentExt, error)
-	Create(create STaaSEnvironmentCreate) (*Reference, error)
-	Delete(id string, delete STaaSEnvironmentDelete) error
-	Update(id string, update STaaSEnvironmentUpdate) error
+	GetWithContext(ctx context.Context, id string) (*STaaSEnvironmentExt, error)
+	Create(create STaaSEnvironmentCreate) (*Operation[*STaaSEnvironmentExt], error)
+	CreateWithContext(ctx context.Context, create STaaSEnvironmentCreate) (*Operation[*STaaSEnvironmentExt], error)
+	Delete(id string, delete STaaSEnvironmentDelete) (*Operation[*STaaSEnvironmentExt], error)
+	DeleteWithContext(ctx context.Context, id string, delete STaaSEnvironmentDelete) (*Operation[*STaaSEnvironmentExt], error)
+	Update(id string, update STaaSEnvironmentUpdate) (*Operation[*STaaSEnvironmentExt], error)
+	UpdateWithContext(ctx context.Context, id string, update STaaSEnvironmentUpdate) (*Operation[*STaaSEnvironmentExt], error)
 	CreateVolume(id string, create STaaSVolumeCreate) error
+	CreateVolumeWithContext(ctx context.Context, id string, create STaaSVolumeCreate) error
 	UpdateVolume(id string, volumeId string, update STaaSVolumeUpdate) error
+	UpdateVolumeWithContext(ctx context.Context, id string, volumeId string, update STaaSVolumeUpdate) error
 	DeleteVolume(id string, volumeId string, delete STaaSVolumeDelete) error
+	DeleteVolumeWithContext(ctx context.Context, id string, volumeId string, delete STaaSVolumeDelete) error
 	CreateNetwork(id string, create STaaSNetworkCreate) error
+	CreateNetworkWithContext(ctx context.Context, id string, create STaaSNetworkCreate) error
 	DeleteNetwork(id string, networkId string) error
+	DeleteNetworkWithContext(ctx context.Context, id string, networkId string) error
+	WaitForState(ctx context.Context, id string, state string, options WaitOptions) (*STaaSEnvironmentExt, error)
 }
 
 type STaaSEnvironmentServiceImpl struct {
@@ -113,64 +128,116 @@ type STaaSVolumeDelete struct {
 	Force bool `json:"force"`
 }
 
-func (c *STaaSEnvironmentServiceImpl) Page(request PageRequest) (*Page, *[]STaaSEnvironment, error) {
-	page := new(Page)
-	err := c.client.Get(staasBasePath+"/environment", page, &request)
-	if err != nil {
-		return nil, nil, err
-	}
+func (c *STaaSEnvironmentServiceImpl) Page(request PageRequest) (*Page[STaaSEnvironment], error) {
+	return c.PageWithContext(context.Background(), request)
+}
 
-	environments := new([]STaaSEnvironment)
-	if err := json.Unmarshal(page.Content, &environments); err != nil {
-		return nil, nil, err
-	}
+func (c *STaaSEnvironmentServiceImpl) PageWithContext(ctx context.Context, request PageRequest) (*Page[STaaSEnvironment], error) {
+	page := newPage[STaaSEnvironment](request)
+	err := c.client.GetWithContext(ctx, staasBasePath+"/environment", page, &request)
+	return page, err
+}
 
-	return page, environments, err
+func (c *STaaSEnvironmentServiceImpl) All(ctx context.Context, request PageRequest) iter.Seq2[STaaSEnvironment, error] {
+	return paginate(ctx, request, c.PageWithContext)
 }
 
 func (c *STaaSEnvironmentServiceImpl) Get(id string) (*STaaSEnvironmentExt, error) {
+	return c.GetWithContext(context.Background(), id)
+}
+
+func (c *STaaSEnvironmentServiceImpl) GetWithContext(ctx context.Context, id string) (*STaaSEnvironmentExt, error) {
 	environment := new(STaaSEnvironmentExt)
-	err := c.client.Get(staasBasePath+"/environment/"+id, environment, nil)
+	err := c.client.GetWithContext(ctx, staasBasePath+"/environment/"+id, environment, nil)
 	return environment, err
 }
 
-func (c *STaaSEnvironmentServiceImpl) Create(create STaaSEnvironmentCreate) (*Reference, error) {
+func (c *STaaSEnvironmentServiceImpl) Create(create STaaSEnvironmentCreate) (*Operation[*STaaSEnvironmentExt], error) {
+	return c.CreateWithContext(context.Background(), create)
+}
+
+func (c *STaaSEnvironmentServiceImpl) CreateWithContext(ctx context.Context, create STaaSEnvironmentCreate) (*Operation[*STaaSEnvironmentExt], error) {
 	response := new(Reference)
-	err := c.client.Post(staasBasePath+"/environment", create, &response)
-	return response, err
+	err := c.client.PostWithContext(ctx, staasBasePath+"/environment", create, &response)
+	if err != nil {
+		return nil, err
+	}
+	return newStateOperation(response.Id, c.WaitForState), nil
 }
 
-func (c *STaaSEnvironmentServiceImpl) Update(id string, update STaaSEnvironmentUpdate) error {
-	err := c.client.Put(staasBasePath+"/environment/"+id, update, nil)
-	return err
+func (c *STaaSEnvironmentServiceImpl) Update(id string, update STaaSEnvironmentUpdate) (*Operation[*STaaSEnvironmentExt], error) {
+	return c.UpdateWithContext(context.Background(), id, update)
 }
 
-func (c *STaaSEnvironmentServiceImpl) Delete(id string, delete STaaSEnvironmentDelete) error {
-	err := c.client.Delete(staasBasePath+"/environment/"+id, delete)
-	return err
+func (c *STaaSEnvironmentServiceImpl) UpdateWithContext(ctx context.Context, id string, update STaaSEnvironmentUpdate) (*Operation[*STaaSEnvironmentExt], error) {
+	err := c.client.PutWithContext(ctx, staasBasePath+"/environment/"+id, update, nil)
+	if err != nil {
+		return nil, err
+	}
+	// without a modification time, the update is taken as applied once the environment is ready
+	return newStateOperation(id, c.WaitForState), nil
+}
+
+func (c *STaaSEnvironmentServiceImpl) Delete(id string, delete STaaSEnvironmentDelete) (*Operation[*STaaSEnvironmentExt], error) {
+	return c.DeleteWithContext(context.Background(), id, delete)
+}
+
+func (c *STaaSEnvironmentServiceImpl) DeleteWithContext(ctx context.Context, id string, delete STaaSEnvironmentDelete) (*Operation[*STaaSEnvironmentExt], error) {
+	err := c.client.DeleteWithContext(ctx, staasBasePath+"/environment/"+id, delete)
+	if err != nil {
+		return nil, err
+	}
+	return newDeleteOperation(id, c.GetWithContext), nil
 }
 
 func (c *STaaSEnvironmentServiceImpl) CreateVolume(id string, create STaaSVolumeCreate) error {
-	err := c.client.Post(staasBasePath+"/environment/"+id+"/volume", create, nil)
+	return c.CreateVolumeWithContext(context.Background(), id, create)
+}
+
+func (c *STaaSEnvironmentServiceImpl) CreateVolumeWithContext(ctx context.Context, id string, create STaaSVolumeCreate) error {
+	err := c.client.PostWithContext(ctx, staasBasePath+"/environment/"+id+"/volume", create, nil)
 	return err
 }
 
 func (c *STaaSEnvironmentServiceImpl) UpdateVolume(id string, volumeId string, create STaaSVolumeUpdate) error {
-	err := c.client.Put(staasBasePath+"/environment/"+id+"/volume/"+volumeId, create, nil)
+	return c.UpdateVolumeWithContext(context.Background(), id, volumeId, create)
+}
+
+func (c *STaaSEnvironmentServiceImpl) UpdateVolumeWithContext(ctx context.Context, id string, volumeId string, create STaaSVolumeUpdate) error {
+	err := c.client.PutWithContext(ctx, staasBasePath+"/environment/"+id+"/volume/"+volumeId, create, nil)
 	return err
 }
 
 func (c *STaaSEnvironmentServiceImpl) DeleteVolume(id string, volumeId string, delete STaaSVolumeDelete) error {
-	err := c.client.Delete(staasBasePath+"/environment/"+id+"/volume/"+volumeId, delete)
+	return c.DeleteVolumeWithContext(context.Background(), id, volumeId, delete)
+}
+
+func (c *STaaSEnvironmentServiceImpl) DeleteVolumeWithContext(ctx context.Context, id string, volumeId string, delete STaaSVolumeDelete) error {
+	err := c.client.DeleteWithContext(ctx, staasBasePath+"/environment/"+id+"/volume/"+volumeId, delete)
 	return err
 }
 
 func (c *STaaSEnvironmentServiceImpl) CreateNetwork(id string, create STaaSNetworkCreate) error {
-	err := c.client.Post(staasBasePath+"/environment/"+id+"/network", create, nil)
+	return c.CreateNetworkWithContext(context.Background(), id, create)
+}
+
+func (c *STaaSEnvironmentServiceImpl) CreateNetworkWithContext(ctx context.Context, id string, create STaaSNetworkCreate) error {
+	err := c.client.PostWithContext(ctx, staasBasePath+"/environment/"+id+"/network", create, nil)
 	return err
 }
 
 func (c *STaaSEnvironmentServiceImpl) DeleteNetwork(id string, networkId string) error {
-	err := c.client.Delete(staasBasePath+"/environment/"+id+"/network/"+networkId, nil)
+	return c.DeleteNetworkWithContext(context.Background(), id, networkId)
+}
+
+func (c *STaaSEnvironmentServiceImpl) DeleteNetworkWithContext(ctx context.Context, id string, networkId string) error {
+	err := c.client.DeleteWithContext(ctx, staasBasePath+"/environment/"+id+"/network/"+networkId, nil)
 	return err
 }
+
+// WaitForState polls the STaaS environment until it reaches the state or one of the failure states
+func (c *STaaSEnvironmentServiceImpl) WaitForState(ctx context.Context, id string, state string, options WaitOptions) (*STaaSEnvironmentExt, error) {
+	return waitForState(ctx, id, state, options, c.GetWithContext, func(environment *STaaSEnvironmentExt) string {
+		return environment.State
+	})
+}
@@ -1,7 +1,11 @@
 package client
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"iter"
+	"slices"
 	"time"
 )
 
@@ -11,64 +15,494 @@ const (
 
 type TaskService interface {
 	List() (*[]Task, error)
+	ListWithContext(ctx context.Context) (*[]Task, error)
+	History(filter TaskFilter, page int, size int) (*Page[Task], error)
+	HistoryWithContext(ctx context.Context, filter TaskFilter, page int, size int) (*Page[Task], error)
 	Get(id string) (*Task, error)
+	GetWithContext(ctx context.Context, id string) (*Task, error)
 	WaitFor(id string, timeoutDuration time.Duration) (*Task, error)
+	WaitForWithContext(ctx context.Context, id string, timeoutDuration time.Duration) (*Task, error)
 	WaitForTask(task *Task, timeoutDuration time.Duration) (*Task, error)
+	WaitForTaskWithContext(ctx context.Context, task *Task, timeoutDuration time.Duration) (*Task, error)
+	WaitForWithOptions(ctx context.Context, id string, options WaitOptions) (*Task, error)
+	WaitForWithProgress(ctx context.Context, id string, options WaitOptions, onProgress func(TaskProgress)) (*Task, error)
+	Watch(ctx context.Context, id string, options WaitOptions) <-chan TaskProgress
+	WaitForEach(ctx context.Context, ids []string, options WaitOptions) iter.Seq[TaskResult]
+	WaitForAll(ctx context.Context, ids []string, options WaitOptions) ([]TaskResult, error)
+	WaitForAny(ctx context.Context, ids []string, options WaitOptions) (TaskResult, error)
 }
 
 type TaskServiceOp struct {
 	client *PreviderClient
 }
 
+// TaskType is the kind of change a task applies
+type TaskType string
+
+const (
+	TaskTypeVirtualMachineCreate   TaskType = "VIRTUALMACHINE_CREATE"
+	TaskTypeVirtualMachineUpdate   TaskType = "VIRTUALMACHINE_UPDATE"
+	TaskTypeVirtualMachineDelete   TaskType = "VIRTUALMACHINE_DELETE"
+	TaskTypeVirtualMachinePowerOn  TaskType = "VIRTUALMACHINE_POWERON"
+	TaskTypeVirtualMachinePowerOff TaskType = "VIRTUALMACHINE_POWEROFF"
+	TaskTypeVirtualMachineShutdown TaskType = "VIRTUALMACHINE_SHUTDOWN"
+	TaskTypeVirtualMachineReboot   TaskType = "VIRTUALMACHINE_REBOOT"
+	TaskTypeVirtualMachineSuspend  TaskType = "VIRTUALMACHINE_SUSPEND"
+	TaskTypeVirtualMachineReset    TaskType = "VIRTUALMACHINE_RESET"
+	TaskTypeVirtualNetworkCreate   TaskType = "VIRTUALNETWORK_CREATE"
+	TaskTypeVirtualNetworkUpdate   TaskType = "VIRTUALNETWORK_UPDATE"
+	TaskTypeVirtualNetworkDelete   TaskType = "VIRTUALNETWORK_DELETE"
+)
+
+// TaskStatus is derived from the progress and outcome of a task
+type TaskStatus string
+
+const (
+	TaskStatusPending   TaskStatus = "pending"
+	TaskStatusRunning   TaskStatus = "running"
+	TaskStatusSucceeded TaskStatus = "succeeded"
+	TaskStatusFailed    TaskStatus = "failed"
+)
+
 type Task struct {
-	Id            string
-	Completed     bool
-	CompletedDate *int
-	StartedDate   *int
-	User          string
-	Success       bool
-	Error         bool
-	ErrorMessage  string
-	Progress      int
-	TaskDate      *int
-	TaskType      string
+	Id        string `json:"id"`
+	Completed bool   `json:"completed"`
+	// CompletedDate, StartedDate and TaskDate are epoch milliseconds, see CompletedAt, StartedAt and CreatedAt
+	CompletedDate *int     `json:"completedDate,omitempty"`
+	StartedDate   *int     `json:"startedDate,omitempty"`
+	User          string   `json:"user"`
+	Success       bool     `json:"success"`
+	Error         bool     `json:"error"`
+	ErrorMessage  string   `json:"errorMessage,omitempty"`
+	Progress      int      `json:"progress"`
+	TaskDate      *int     `json:"taskDate,omitempty"`
+	TaskType      TaskType `json:"taskType"`
+	// VirtualMachine and VirtualNetwork are the ids of the resource the task applies to
+	VirtualMachine string `json:"virtualMachine,omitempty"`
+	VirtualNetwork string `json:"virtualNetwork,omitempty"`
+}
+
+// TaskFilter selects tasks from the task history, zero fields match all tasks
+type TaskFilter struct {
+	User  string
+	Types []TaskType
+	// Resource is the id of the virtual machine or virtual network the task applies to
+	Resource string
+	// From and Until limit the date the task was submitted, both inclusive
+	From  time.Time
+	Until time.Time
+	// Outcome matches the derived status of the task, e.g. TaskStatusFailed
+	Outcome TaskStatus
+}
+
+// Matches reports whether the task passes all set fields of the filter
+func (f TaskFilter) Matches(task *Task) bool {
+	if f.User != "" && task.User != f.User {
+		return false
+	}
+	if len(f.Types) > 0 && !slices.Contains(f.Types, task.TaskType) {
+		return false
+	}
+	if f.Resource != "" && task.VirtualMachine != f.Resource && task.VirtualNetwork != f.Resource {
+		return false
+	}
+	created := task.CreatedAt()
+	if !f.From.IsZero() && (created.IsZero() || created.Before(f.From)) {
+		return false
+	}
+	if !f.Until.IsZero() && (created.IsZero() || created.After(f.Until)) {
+		return false
+	}
+	return f.Outcome == "" || task.Status() == f.Outcome
+}
+
+// CreatedAt is the time the task was submitted, zero if unknown
+func (t *Task) CreatedAt() time.Time {
+	return epochTime(t.TaskDate)
+}
+
+// StartedAt is zero until the task started
+func (t *Task) StartedAt() time.Time {
+	return epochTime(t.StartedDate)
+}
+
+// CompletedAt is zero until the task completed
+func (t *Task) CompletedAt() time.Time {
+	return epochTime(t.CompletedDate)
+}
+
+// Status derives the state of the task. A task that reports an error has failed,
+// even when the API did not mark it completed yet.
+func (t *Task) Status() TaskStatus {
+	switch {
+	case t.Completed && t.Success:
+		return TaskStatusSucceeded
+	case t.Completed || t.Error:
+		return TaskStatusFailed
+	case t.StartedDate != nil || t.Progress > 0:
+		return TaskStatusRunning
+	default:
+		return TaskStatusPending
+	}
+}
+
+// finished reports whether the task succeeded or failed, waiting for it ends then
+func (t *Task) finished() bool {
+	status := t.Status()
+	return status == TaskStatusSucceeded || status == TaskStatusFailed
+}
+
+// TaskProgress is reported while waiting for a task whenever its progress or state changes
+type TaskProgress struct {
+	Id        string
+	Status    TaskStatus
+	Progress  int
+	Started   bool
+	Completed bool
+	Success   bool
+	// StartedAt and CompletedAt are zero until the task started or completed
+	StartedAt   time.Time
+	CompletedAt time.Time
+	// Task is the observed task
+	Task *Task
+	// Err is only set on the last update sent by Watch, when the wait failed
+	Err error
+}
+
+func newTaskProgress(id string, task *Task) TaskProgress {
+	progress := TaskProgress{Id: id, Task: task}
+	if task != nil {
+		progress.Status = task.Status()
+		progress.Progress = task.Progress
+		progress.Started = task.StartedDate != nil
+		progress.Completed = task.Completed
+		progress.Success = task.Success
+		progress.StartedAt = task.StartedAt()
+		progress.CompletedAt = task.CompletedAt()
+	}
+	return progress
+}
+
+// changed reports whether a new observation of the task differs from the previous one
+func (p TaskProgress) changed(previous *TaskProgress) bool {
+	return previous == nil || p.Status != previous.Status || p.Progress != previous.Progress || p.Started != previous.Started ||
+		p.Completed != previous.Completed || p.Success != previous.Success
+}
+
+// epochTime converts the epoch milliseconds used by the API
+func epochTime(millis *int) time.Time {
+	if millis == nil {
+		return time.Time{}
+	}
+	return time.UnixMilli(int64(*millis))
+}
+
+// TaskResult is the outcome of waiting for one of several tasks
+type TaskResult struct {
+	Id string
+	// Task is the last observed state, nil if the task was never retrieved
+	Task *Task
+	// Err is a *TaskFailedError, *TaskTimeoutError or the error that ended polling
+	Err error
+}
+
+// TaskFailedError is returned when a task completed without success
+type TaskFailedError struct {
+	ErrorMessage string
+	Task         *Task
+}
+
+func newTaskFailedError(task *Task) *TaskFailedError {
+	return &TaskFailedError{ErrorMessage: task.ErrorMessage, Task: task}
+}
+
+func (e *TaskFailedError) Error() string {
+	if e.ErrorMessage == "" {
+		return fmt.Sprintf("task %s failed", e.Task.Id)
+	}
+	return fmt.Sprintf("task %s failed: %s", e.Task.Id, e.ErrorMessage)
+}
+
+// TaskTimeoutError is returned when the wait for a task timed out or its context was canceled
+type TaskTimeoutError struct {
+	Id string
+	// Task is the last observed state, nil if the task was never retrieved
+	Task *Task
+	Err  error
+}
+
+func (e *TaskTimeoutError) Error() string {
+	return fmt.Sprintf("waiting for task %s: %v", e.Id, e.Err)
+}
+
+func (e *TaskTimeoutError) Unwrap() error {
+	return e.Err
 }
 
 func (c *TaskServiceOp) List() (*[]Task, error) {
+	return c.ListWithContext(context.Background())
+}
+
+func (c *TaskServiceOp) ListWithContext(ctx context.Context) (*[]Task, error) {
 	task := new([]Task)
-	err := c.client.Get(iaasBasePath+"task", task, nil)
+	err := c.client.GetWithContext(ctx, iaasBasePath+"task", task, nil)
 	return task, err
 }
 
+// History returns a page of the tasks matching the filter, newest first. The API has no
+// filtered or paginated task history, so every call downloads the complete task list
+// and filters and pages it on the client. Page starts at 0, a size of 0 or less returns
+// all matching tasks on one page.
+func (c *TaskServiceOp) History(filter TaskFilter, page int, size int) (*Page[Task], error) {
+	return c.HistoryWithContext(context.Background(), filter, page, size)
+}
+
+func (c *TaskServiceOp) HistoryWithContext(ctx context.Context, filter TaskFilter, page int, size int) (*Page[Task], error) {
+	tasks, err := c.ListWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []Task
+	for _, task := range *tasks {
+		if filter.Matches(&task) {
+			matched = append(matched, task)
+		}
+	}
+	slices.SortStableFunc(matched, func(a Task, b Task) int {
+		return b.CreatedAt().Compare(a.CreatedAt())
+	})
+
+	result := newPage[Task](PageRequest{Page: page, Size: size})
+	result.Size = size
+	if result.Size <= 0 {
+		result.Size = max(len(matched), 1)
+	}
+	result.Number = max(page, 0)
+	result.TotalElements = len(matched)
+	result.TotalPages = (len(matched) + result.Size - 1) / result.Size
+	if start := result.Number * result.Size; start < len(matched) {
+		result.Content = matched[start:min(start+result.Size, len(matched))]
+	}
+	result.NumberOfElements = len(result.Content)
+	return result, nil
+}
+
 func (c *TaskServiceOp) Get(id string) (*Task, error) {
+	return c.GetWithContext(context.Background(), id)
+}
+
+func (c *TaskServiceOp) GetWithContext(ctx context.Context, id string) (*Task, error) {
 	task := new(Task)
-	err := c.client.Get(iaasBasePath+"task/"+id, task, nil)
+	err := c.client.GetWithContext(ctx, iaasBasePath+"task/"+id, task, nil)
 	return task, err
 }
 
 func (c *TaskServiceOp) WaitForTask(task *Task, timeoutDuration time.Duration) (*Task, error) {
-	return c.WaitFor(task.Id, timeoutDuration)
+	return c.WaitForTaskWithContext(context.Background(), task, timeoutDuration)
+}
+
+func (c *TaskServiceOp) WaitForTaskWithContext(ctx context.Context, task *Task, timeoutDuration time.Duration) (*Task, error) {
+	return c.WaitForWithContext(ctx, task.Id, timeoutDuration)
 }
 
 func (c *TaskServiceOp) WaitFor(id string, timeoutDuration time.Duration) (*Task, error) {
-	timeout := time.After(timeoutDuration)
-	tick := time.Tick(3 * time.Second)
+	return c.WaitForWithContext(context.Background(), id, timeoutDuration)
+}
+
+// WaitForWithContext waits for at most timeoutDuration, a timeout of 0 or less waits for
+// DefaultTimeout. Use WaitForWithOptions to wait until the context is done.
+func (c *TaskServiceOp) WaitForWithContext(ctx context.Context, id string, timeoutDuration time.Duration) (*Task, error) {
+	return c.WaitForWithOptions(ctx, id, timeoutWaitOptions(timeoutDuration))
+}
+
+// timeoutWaitOptions are the DefaultWaitOptions with the timeout of the WaitFor methods,
+// which never wait without a limit
+func timeoutWaitOptions(timeoutDuration time.Duration) WaitOptions {
+	options := DefaultWaitOptions
+	if timeoutDuration > 0 {
+		options.Timeout = timeoutDuration
+	}
+	return options
+}
+
+// WaitForWithOptions polls the task until it succeeded or failed according to Status,
+// returning a *TaskFailedError when it failed and a *TaskTimeoutError when the wait ended before completion
+func (c *TaskServiceOp) WaitForWithOptions(ctx context.Context, id string, options WaitOptions) (*Task, error) {
+	return c.WaitForWithProgress(ctx, id, options, nil)
+}
+
+// WaitForWithProgress is WaitForWithOptions calling onProgress on the first observation
+// of the task and whenever its progress or state changes
+func (c *TaskServiceOp) WaitForWithProgress(ctx context.Context, id string, options WaitOptions, onProgress func(TaskProgress)) (task *Task, err error) {
+	ctx, span := c.client.startTaskSpan(ctx, "Previder Task.WaitFor", id)
+	polls := 0
+	defer func() {
+		endTaskSpan(span, task, polls, err)
+	}()
+
+	p := newPoller(options)
+	ctx, cancel := p.context(ctx)
+	defer cancel()
+	var reported *TaskProgress
 	for {
+		if err := p.wait(ctx); err != nil {
+			return task, &TaskTimeoutError{Id: id, Task: task, Err: err}
+		}
+		polls++
+		current, err := c.GetWithContext(ctx, id)
+		if err != nil && ctx.Err() != nil {
+			return task, &TaskTimeoutError{Id: id, Task: task, Err: ctx.Err()}
+		}
+		if !p.tolerate(err) {
+			return task, err
+		}
+		if err != nil {
+			continue
+		}
+		task = current
+		if progress := newTaskProgress(id, task); onProgress != nil && progress.changed(reported) {
+			onProgress(progress)
+			reported = &progress
+		}
+		if task.finished() {
+			if task.Status() == TaskStatusFailed {
+				return task, newTaskFailedError(task)
+			}
+			return task, nil
+		}
+	}
+}
+
+// Watch waits for the task in the background and sends its progress on the returned
+// channel, which is closed when the wait ends. A failed wait is reported by a last
+// update with Err set. The channel must be drained or the context canceled.
+func (c *TaskServiceOp) Watch(ctx context.Context, id string, options WaitOptions) <-chan TaskProgress {
+	updates := make(chan TaskProgress)
+	send := func(progress TaskProgress) {
 		select {
-		case <-timeout:
-			return nil, errors.New("timed out")
-		case <-tick:
-			task, err := c.Get(id)
+		case updates <- progress:
+		case <-ctx.Done():
+		}
+	}
+	go func() {
+		defer close(updates)
+		task, err := c.WaitForWithProgress(ctx, id, options, send)
+		if err != nil {
+			progress := newTaskProgress(id, task)
+			progress.Err = err
+			send(progress)
+		}
+	}()
+	return updates
+}
+
+// WaitForEach polls all tasks in a single loop, listing all tasks at once and only
+// retrieving the tasks missing from the list separately. It yields the result of
+// every task as soon as it completes, and stops waiting when the loop is broken.
+func (c *TaskServiceOp) WaitForEach(ctx context.Context, ids []string, options WaitOptions) iter.Seq[TaskResult] {
+	return func(yield func(TaskResult) bool) {
+		p := newPoller(options)
+		ctx, cancel := p.context(ctx)
+		defer cancel()
+
+		var pending []string
+		tasks := make(map[string]*Task)
+		for _, id := range ids {
+			if _, ok := tasks[id]; !ok {
+				pending = append(pending, id)
+				tasks[id] = nil
+			}
+		}
+		// finish yields the results of all pending tasks
+		finish := func(err func(id string) error) {
+			for _, id := range pending {
+				if !yield(TaskResult{Id: id, Task: tasks[id], Err: err(id)}) {
+					return
+				}
+			}
+		}
+
+		for len(pending) > 0 {
+			if err := p.wait(ctx); err != nil {
+				finish(func(id string) error { return &TaskTimeoutError{Id: id, Task: tasks[id], Err: err} })
+				return
+			}
+			list, err := c.ListWithContext(ctx)
+			if err != nil && ctx.Err() != nil {
+				continue
+			}
+			if !p.tolerate(err) {
+				finish(func(string) error { return err })
+				return
+			}
 			if err != nil {
-				return nil, err
+				continue
 			}
-			if task.Completed {
-				if task.Success {
-					return task, nil
-				} else {
-					return task, errors.New(task.ErrorMessage)
+			listed := make(map[string]*Task, len(*list))
+			for i := range *list {
+				listed[(*list)[i].Id] = &(*list)[i]
+			}
+
+			var stillPending []string
+			for _, id := range pending {
+				task, ok := listed[id]
+				if !ok {
+					task, err = c.GetWithContext(ctx, id)
+					if err != nil && (ctx.Err() != nil || transient(err)) {
+						stillPending = append(stillPending, id)
+						continue
+					}
+					if err != nil {
+						if !yield(TaskResult{Id: id, Task: tasks[id], Err: err}) {
+							return
+						}
+						continue
+					}
+				}
+				tasks[id] = task
+				if !task.finished() {
+					stillPending = append(stillPending, id)
+					continue
+				}
+				result := TaskResult{Id: id, Task: task}
+				if task.Status() == TaskStatusFailed {
+					result.Err = newTaskFailedError(task)
+				}
+				if !yield(result) {
+					return
 				}
 			}
+			pending = stillPending
+		}
+	}
+}
+
+// WaitForAll waits until all tasks completed, returning their results in the order
+// of the ids and the errors of the tasks that did not succeed joined together
+func (c *TaskServiceOp) WaitForAll(ctx context.Context, ids []string, options WaitOptions) ([]TaskResult, error) {
+	results := make(map[string]TaskResult, len(ids))
+	for result := range c.WaitForEach(ctx, ids, options) {
+		results[result.Id] = result
+	}
+
+	ordered := make([]TaskResult, 0, len(ids))
+	var errs []error
+	for _, id := range ids {
+		result := results[id]
+		ordered = append(ordered, result)
+		if result.Err != nil {
+			errs = append(errs, result.Err)
 		}
 	}
+	return ordered, errors.Join(errs...)
+}
+
+// WaitForAny waits until the first of the tasks completed and returns its result,
+// with Err also returned as error
+func (c *TaskServiceOp) WaitForAny(ctx context.Context, ids []string, options WaitOptions) (TaskResult, error) {
+	for result := range c.WaitForEach(ctx, ids, options) {
+		return result, result.Err
+	}
+	return TaskResult{}, errors.New("no tasks to wait for")
 }
@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTaskHistory(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/"+iaasBasePath+"task" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`[
+			{"id":"1","user":"alice","taskType":"VIRTUALMACHINE_CREATE","virtualMachine":"vm1","taskDate":1000,"completed":true,"success":true},
+			{"id":"2","user":"bob","taskType":"VIRTUALMACHINE_CREATE","virtualMachine":"vm2","taskDate":2000,"completed":true,"error":true},
+			{"id":"3","user":"alice","taskType":"VIRTUALNETWORK_CREATE","virtualNetwork":"vn1","taskDate":3000},
+			{"id":"4","user":"alice","taskType":"VIRTUALMACHINE_DELETE","virtualMachine":"vm1","taskDate":4000,"completed":true,"success":true}
+		]`))
+	}, nil)
+	tasks := TaskServiceOp{client: c}
+
+	tests := []struct {
+		name   string
+		filter TaskFilter
+		page   int
+		size   int
+		want   []string
+		total  int
+		pages  int
+	}{
+		{"all on one page", TaskFilter{}, 0, 0, []string{"4", "3", "2", "1"}, 4, 1},
+		{"user", TaskFilter{User: "alice"}, 0, 0, []string{"4", "3", "1"}, 3, 1},
+		{"types", TaskFilter{Types: []TaskType{TaskTypeVirtualMachineCreate}}, 0, 0, []string{"2", "1"}, 2, 1},
+		{"virtual machine", TaskFilter{Resource: "vm1"}, 0, 0, []string{"4", "1"}, 2, 1},
+		{"virtual network", TaskFilter{Resource: "vn1"}, 0, 0, []string{"3"}, 1, 1},
+		{"date range", TaskFilter{From: time.UnixMilli(2000), Until: time.UnixMilli(3000)}, 0, 0, []string{"3", "2"}, 2, 1},
+		{"outcome", TaskFilter{Outcome: TaskStatusFailed}, 0, 0, []string{"2"}, 1, 1},
+		{"second page", TaskFilter{}, 1, 3, []string{"1"}, 4, 2},
+		{"past the last page", TaskFilter{}, 2, 3, nil, 4, 2},
+		{"no matches", TaskFilter{User: "carol"}, 0, 0, nil, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := tasks.History(tt.filter, tt.page, tt.size)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []string
+			for _, task := range page.Content {
+				ids = append(ids, task.Id)
+			}
+			if len(ids) != len(tt.want) {
+				t.Fatalf("expected tasks %v, got %v", tt.want, ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.want[i] {
+					t.Fatalf("expected tasks %v, got %v", tt.want, ids)
+				}
+			}
+			if page.TotalElements != tt.total || page.TotalPages != tt.pages || page.NumberOfElements != len(tt.want) {
+				t.Errorf("expected %d elements on %d pages, got %+v", tt.total, tt.pages, page)
+			}
+		})
+	}
+}
+
+func TestTaskStatus(t *testing.T) {
+	started := 1000
+	tests := []struct {
+		name string
+		task Task
+		want TaskStatus
+	}{
+		{"pending", Task{}, TaskStatusPending},
+		{"started", Task{StartedDate: &started}, TaskStatusRunning},
+		{"progress", Task{Progress: 50}, TaskStatusRunning},
+		{"succeeded", Task{Completed: true, Success: true}, TaskStatusSucceeded},
+		{"completed without success", Task{Completed: true}, TaskStatusFailed},
+		{"completed with error", Task{Completed: true, Error: true}, TaskStatusFailed},
+		{"error before completion", Task{StartedDate: &started, Error: true}, TaskStatusFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.task.Status(); got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+			finished := tt.want == TaskStatusSucceeded || tt.want == TaskStatusFailed
+			if tt.task.finished() != finished {
+				t.Errorf("expected finished to be %t", finished)
+			}
+		})
+	}
+}
+
+func TestTaskTimes(t *testing.T) {
+	created, started, completed := 1700000000000, 1700000001000, 1700000002500
+	task := Task{TaskDate: &created, StartedDate: &started, CompletedDate: &completed}
+	if got := task.CreatedAt(); !got.Equal(time.UnixMilli(1700000000000)) {
+		t.Errorf("unexpected CreatedAt %s", got)
+	}
+	if got := task.StartedAt(); !got.Equal(time.UnixMilli(1700000001000)) {
+		t.Errorf("unexpected StartedAt %s", got)
+	}
+	if got := task.CompletedAt().Sub(task.StartedAt()); got != 1500*time.Millisecond {
+		t.Errorf("expected the task to run for 1.5s, got %s", got)
+	}
+	if empty := (Task{}); !empty.CreatedAt().IsZero() || !empty.StartedAt().IsZero() || !empty.CompletedAt().IsZero() {
+		t.Error("expected zero times for a task without dates")
+	}
+}
+
+func TestWaitForTimeout(t *testing.T) {
+	for timeout, want := range map[time.Duration]time.Duration{-time.Second: DefaultTimeout, 0: DefaultTimeout, time.Minute: time.Minute} {
+		if got := timeoutWaitOptions(timeout).Timeout; got != want {
+			t.Errorf("expected a timeout of %s for %s, got %s", want, timeout, got)
+		}
+	}
+}
+
+func TestWaitForTaskWithError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		task := `{"id":"1","progress":40,"error":true,"errorMessage":"disk full"}`
+		if r.URL.Path == "/api/"+iaasBasePath+"task" {
+			task = "[" + task + "]"
+		}
+		_, _ = w.Write([]byte(task))
+	}, nil)
+	tasks := TaskServiceOp{client: c}
+
+	options := DefaultWaitOptions
+	options.InitialInterval = time.Millisecond
+	task, err := tasks.WaitForWithOptions(context.Background(), "1", options)
+	var failed *TaskFailedError
+	if !errors.As(err, &failed) || failed.ErrorMessage != "disk full" {
+		t.Fatalf("expected a TaskFailedError, got %v", err)
+	}
+	if task.Completed {
+		t.Errorf("expected the task as reported, got %+v", task)
+	}
+
+	results := 0
+	for result := range tasks.WaitForEach(context.Background(), []string{"1"}, options) {
+		results++
+		if !errors.As(result.Err, &failed) {
+			t.Errorf("expected WaitForEach to report the failure, got %v", result.Err)
+		}
+	}
+	if results != 1 {
+		t.Errorf("expected 1 result, got %d", results)
+	}
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/previder/previder-go-sdk/client"
+
+const (
+	attributeService     = attribute.Key("previder.service")
+	attributeOperation   = attribute.Key("previder.operation")
+	attributeResourceId  = attribute.Key("previder.resource.id")
+	attributeRetryCount  = attribute.Key("previder.retry_count")
+	attributeTaskId      = attribute.Key("previder.task.id")
+	attributeTaskPolls   = attribute.Key("previder.task.polls")
+	attributeHttpMethod  = attribute.Key("http.request.method")
+	attributeHttpStatus  = attribute.Key("http.response.status_code")
+	attributeUrl         = attribute.Key("url.full")
+	attributeCustomerId  = attribute.Key("previder.customer.id")
+	attributeTaskSuccess = attribute.Key("previder.task.success")
+)
+
+func newTracer(options *ClientOptions) trace.Tracer {
+	tracerProvider := options.TracerProvider
+	if tracerProvider == nil {
+		tracerProvider = otel.GetTracerProvider()
+	}
+	return tracerProvider.Tracer(tracerName)
+}
+
+func newPropagator(options *ClientOptions) propagation.TextMapPropagator {
+	if options.Propagator == nil {
+		return otel.GetTextMapPropagator()
+	}
+	return options.Propagator
+}
+
+func (c *PreviderClient) startRequestSpan(ctx context.Context, method string, url string, info RequestInfo) (context.Context, trace.Span) {
+	attributes := []attribute.KeyValue{
+		attributeService.String(info.Service),
+		attributeOperation.String(info.Operation),
+		attributeHttpMethod.String(method),
+		attributeUrl.String(c.clientOptions.BaseUrl + url),
+	}
+	if info.ResourceId != "" {
+		attributes = append(attributes, attributeResourceId.String(info.ResourceId))
+	}
+	if c.clientOptions.CustomerId != "" {
+		attributes = append(attributes, attributeCustomerId.String(c.clientOptions.CustomerId))
+	}
+	return c.tracer.Start(ctx, "Previder "+info.Operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+}
+
+func endRequestSpan(span trace.Span, res *response, retries int, err error) {
+	span.SetAttributes(attributeRetryCount.Int(retries))
+	if res != nil {
+		span.SetAttributes(attributeHttpStatus.Int(res.statusCode))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (c *PreviderClient) startTaskSpan(ctx context.Context, name string, taskId string) (context.Context, trace.Span) {
+	return c.tracer.Start(ctx, name, trace.WithAttributes(attributeTaskId.String(taskId)))
+}
+
+func endTaskSpan(span trace.Span, task *Task, polls int, err error) {
+	span.SetAttributes(attributeTaskPolls.Int(polls))
+	if task != nil && task.finished() {
+		span.SetAttributes(attributeTaskSuccess.Bool(task.Status() == TaskStatusSucceeded))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
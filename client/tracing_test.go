@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTracedClient returns a test client recording its spans
+func newTracedClient(t *testing.T, handler http.HandlerFunc) (*PreviderClient, *tracetest.SpanRecorder) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	c := newTestClient(t, handler, func(options *ClientOptions) {
+		fastRetries(options)
+		options.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		options.Propagator = propagation.TraceContext{}
+	})
+	return c, recorder
+}
+
+// attributes returns the attributes of the span by key
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	values := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		values[kv.Key] = kv.Value
+	}
+	return values
+}
+
+func TestRequestSpan(t *testing.T) {
+	var attempts atomic.Int32
+	var traceparent atomic.Value
+	c, recorder := newTracedClient(t, func(w http.ResponseWriter, r *http.Request) {
+		traceparent.Store(r.Header.Get("traceparent"))
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"vm1"}`))
+	})
+
+	if err := c.Get(iaasBasePath+"virtualmachine/vm1", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span for the call and its retry, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.SpanKind() != trace.SpanKindClient || span.Name() != "Previder GET virtualmachine/{id}" {
+		t.Errorf("expected a client span for the operation, got %s %s", span.SpanKind(), span.Name())
+	}
+	values := attributes(span)
+	want := map[attribute.Key]attribute.Value{
+		attributeService:    attribute.StringValue("iaas"),
+		attributeOperation:  attribute.StringValue("GET virtualmachine/{id}"),
+		attributeResourceId: attribute.StringValue("vm1"),
+		attributeHttpStatus: attribute.IntValue(http.StatusOK),
+		attributeRetryCount: attribute.IntValue(1),
+	}
+	for key, value := range want {
+		if values[key] != value {
+			t.Errorf("expected %s to be %s, got %s", key, value.Emit(), values[key].Emit())
+		}
+	}
+
+	header, _ := traceparent.Load().(string)
+	if header == "" || header[3:35] != span.SpanContext().TraceID().String() {
+		t.Errorf("expected a traceparent header of the span, got %q", header)
+	}
+}
+
+func TestTaskWaitSpan(t *testing.T) {
+	var polls atomic.Int32
+	c, recorder := newTracedClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if polls.Add(1) < 2 {
+			_, _ = w.Write([]byte(`{"id":"t1","progress":50}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"t1","completed":true,"success":true}`))
+	})
+	tasks := TaskServiceOp{client: c}
+
+	options := DefaultWaitOptions
+	options.InitialInterval = time.Millisecond
+	if _, err := tasks.WaitForWithOptions(context.Background(), "t1", options); err != nil {
+		t.Fatal(err)
+	}
+	var waitSpan sdktrace.ReadOnlySpan
+	requests := 0
+	for _, span := range recorder.Ended() {
+		if span.Name() == "Previder Task.WaitFor" {
+			waitSpan = span
+		} else {
+			requests++
+		}
+	}
+	if waitSpan == nil {
+		t.Fatal("expected a span for the wait")
+	}
+	values := attributes(waitSpan)
+	if values[attributeTaskId] != attribute.StringValue("t1") || values[attributeTaskPolls] != attribute.IntValue(2) ||
+		values[attributeTaskSuccess] != attribute.BoolValue(true) {
+		t.Errorf("unexpected attributes of the wait span %v", values)
+	}
+	if requests != 2 {
+		t.Errorf("expected a span for each of the 2 polls, got %d", requests)
+	}
+	for _, span := range recorder.Ended() {
+		if span != waitSpan && span.Parent().SpanID() != waitSpan.SpanContext().SpanID() {
+			t.Errorf("expected the poll %s to be a child of the wait span", span.Name())
+		}
+	}
+}
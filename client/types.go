@@ -1,21 +1,30 @@
 package client
 
-import "encoding/json"
-
-type PageRequest struct {
-	Page  int
-	Size  int
-	Sort  string
-	Query string
-}
-
-type Page struct {
+type Page[T any] struct {
 	TotalPages       int
 	TotalElements    int
 	NumberOfElements int
 	Size             int
 	Number           int
-	Content          json.RawMessage
+	Content          []T
+
+	request PageRequest
+}
+
+func newPage[T any](request PageRequest) *Page[T] {
+	return &Page[T]{request: request}
+}
+
+// HasNext reports whether there are pages after this one
+func (p *Page[T]) HasNext() bool {
+	return p.Number+1 < p.TotalPages
+}
+
+// NextRequest returns the request for the page after this one, keeping size, sort and query
+func (p *Page[T]) NextRequest() PageRequest {
+	request := p.request
+	request.Page = p.Number + 1
+	return request
 }
 
 type OwnerReference struct {
@@ -1,20 +1,33 @@
 package client
 
 import (
-	"encoding/json"
+	"context"
+	"iter"
 	"net"
 )
 
 type VirtualFirewallService interface {
-	Page(request PageRequest) (*Page, *[]VirtualFirewall, error)
+	Page(request PageRequest) (*Page[VirtualFirewall], error)
+	PageWithContext(ctx context.Context, request PageRequest) (*Page[VirtualFirewall], error)
+	All(ctx context.Context, request PageRequest) iter.Seq2[VirtualFirewall, error]
 	Get(id string) (*VirtualFirewallExt, error)
-	Create(create VirtualFirewallCreate) (*Reference, error)
-	Delete(id string) error
-	Update(id string, update VirtualFirewallUpdate) error
-	PageNatRules(firewallId string, request PageRequest) (*Page, *[]VirtualFirewallNatRule, error)
+	GetWithContext(ctx context.Context, id string) (*VirtualFirewallExt, error)
+	Create(create VirtualFirewallCreate) (*Operation[*VirtualFirewallExt], error)
+	CreateWithContext(ctx context.Context, create VirtualFirewallCreate) (*Operation[*VirtualFirewallExt], error)
+	Delete(id string) (*Operation[*VirtualFirewallExt], error)
+	DeleteWithContext(ctx context.Context, id string) (*Operation[*VirtualFirewallExt], error)
+	Update(id string, update VirtualFirewallUpdate) (*Operation[*VirtualFirewallExt], error)
+	UpdateWithContext(ctx context.Context, id string, update VirtualFirewallUpdate) (*Operation[*VirtualFirewallExt], error)
+	PageNatRules(firewallId string, request PageRequest) (*Page[VirtualFirewallNatRule], error)
+	PageNatRulesWithContext(ctx context.Context, firewallId string, request PageRequest) (*Page[VirtualFirewallNatRule], error)
+	AllNatRules(ctx context.Context, firewallId string, request PageRequest) iter.Seq2[VirtualFirewallNatRule, error]
 	CreateNatRule(firewallId string, create VirtualFirewallNatRuleCreate) (*Reference, error)
+	CreateNatRuleWithContext(ctx context.Context, firewallId string, create VirtualFirewallNatRuleCreate) (*Reference, error)
 	UpdateNatRule(firewallId string, id string, create VirtualFirewallNatRuleCreate) error
+	UpdateNatRuleWithContext(ctx context.Context, firewallId string, id string, create VirtualFirewallNatRuleCreate) error
 	DeleteNatRule(firewallId string, id string) error
+	DeleteNatRuleWithContext(ctx context.Context, firewallId string, id string) error
+	WaitForState(ctx context.Context, id string, state string, options WaitOptions) (*VirtualFirewallExt, error)
 }
 
 type VirtualFirewallServiceImpl struct {
@@ -93,71 +106,124 @@ type VirtualFirewallNatRuleCreate struct {
 	NatPort        int    `json:"natPort"`
 }
 
-func (c *VirtualFirewallServiceImpl) Page(request PageRequest) (*Page, *[]VirtualFirewall, error) {
-	page := new(Page)
-	err := c.client.Get(iaasBasePath+"/virtualfirewall", page, &request)
-	if err != nil {
-		return nil, nil, err
-	}
+func (c *VirtualFirewallServiceImpl) Page(request PageRequest) (*Page[VirtualFirewall], error) {
+	return c.PageWithContext(context.Background(), request)
+}
 
-	response := new([]VirtualFirewall)
-	if err := json.Unmarshal(page.Content, &response); err != nil {
-		return nil, nil, err
-	}
+func (c *VirtualFirewallServiceImpl) PageWithContext(ctx context.Context, request PageRequest) (*Page[VirtualFirewall], error) {
+	page := newPage[VirtualFirewall](request)
+	err := c.client.GetWithContext(ctx, iaasBasePath+"/virtualfirewall", page, &request)
+	return page, err
+}
 
-	return page, response, err
+func (c *VirtualFirewallServiceImpl) All(ctx context.Context, request PageRequest) iter.Seq2[VirtualFirewall, error] {
+	return paginate(ctx, request, c.PageWithContext)
 }
 
 func (c *VirtualFirewallServiceImpl) Get(id string) (*VirtualFirewallExt, error) {
+	return c.GetWithContext(context.Background(), id)
+}
+
+func (c *VirtualFirewallServiceImpl) GetWithContext(ctx context.Context, id string) (*VirtualFirewallExt, error) {
 	response := new(VirtualFirewallExt)
-	err := c.client.Get(iaasBasePath+"/virtualfirewall/"+id, response, nil)
+	err := c.client.GetWithContext(ctx, iaasBasePath+"/virtualfirewall/"+id, response, nil)
 	return response, err
 }
 
-func (c *VirtualFirewallServiceImpl) Create(create VirtualFirewallCreate) (*Reference, error) {
-	response := new(Reference)
-	err := c.client.Post(iaasBasePath+"/virtualfirewall", create, response)
-	return response, err
+func (c *VirtualFirewallServiceImpl) Create(create VirtualFirewallCreate) (*Operation[*VirtualFirewallExt], error) {
+	return c.CreateWithContext(context.Background(), create)
 }
 
-func (c *VirtualFirewallServiceImpl) Update(id string, update VirtualFirewallUpdate) error {
-	err := c.client.Put(iaasBasePath+"/virtualfirewall/"+id, update, nil)
-	return err
+func (c *VirtualFirewallServiceImpl) CreateWithContext(ctx context.Context, create VirtualFirewallCreate) (*Operation[*VirtualFirewallExt], error) {
+	response := new(Reference)
+	err := c.client.PostWithContext(ctx, iaasBasePath+"/virtualfirewall", create, response)
+	if err != nil {
+		return nil, err
+	}
+	return newStateOperation(response.Id, c.WaitForState), nil
 }
 
-func (c *VirtualFirewallServiceImpl) Delete(id string) error {
-	err := c.client.Delete(iaasBasePath+"/virtualfirewall/"+id, nil)
-	return err
+func (c *VirtualFirewallServiceImpl) Update(id string, update VirtualFirewallUpdate) (*Operation[*VirtualFirewallExt], error) {
+	return c.UpdateWithContext(context.Background(), id, update)
 }
 
-// NAT Rules
-func (c *VirtualFirewallServiceImpl) PageNatRules(firewallId string, request PageRequest) (*Page, *[]VirtualFirewallNatRule, error) {
-	page := new(Page)
-	err := c.client.Get(iaasBasePath+"/virtualfirewall/"+firewallId+"/natrules", page, &request)
+func (c *VirtualFirewallServiceImpl) UpdateWithContext(ctx context.Context, id string, update VirtualFirewallUpdate) (*Operation[*VirtualFirewallExt], error) {
+	// the modification time before the update tells when the update was applied
+	before, err := c.GetWithContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	err = c.client.PutWithContext(ctx, iaasBasePath+"/virtualfirewall/"+id, update, nil)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
+	return newUpdateOperation(id, c.GetWithContext, func(firewall *VirtualFirewallExt) string {
+		return firewall.State
+	}, func(firewall *VirtualFirewallExt) bool {
+		return firewall.Audit.LastModifiedAt != before.Audit.LastModifiedAt
+	}), nil
+}
 
-	rules := new([]VirtualFirewallNatRule)
-	if err := json.Unmarshal(page.Content, &rules); err != nil {
-		return nil, nil, err
+func (c *VirtualFirewallServiceImpl) Delete(id string) (*Operation[*VirtualFirewallExt], error) {
+	return c.DeleteWithContext(context.Background(), id)
+}
+
+func (c *VirtualFirewallServiceImpl) DeleteWithContext(ctx context.Context, id string) (*Operation[*VirtualFirewallExt], error) {
+	err := c.client.DeleteWithContext(ctx, iaasBasePath+"/virtualfirewall/"+id, nil)
+	if err != nil {
+		return nil, err
 	}
+	return newDeleteOperation(id, c.GetWithContext), nil
+}
+
+// NAT Rules
+func (c *VirtualFirewallServiceImpl) PageNatRules(firewallId string, request PageRequest) (*Page[VirtualFirewallNatRule], error) {
+	return c.PageNatRulesWithContext(context.Background(), firewallId, request)
+}
+
+func (c *VirtualFirewallServiceImpl) PageNatRulesWithContext(ctx context.Context, firewallId string, request PageRequest) (*Page[VirtualFirewallNatRule], error) {
+	page := newPage[VirtualFirewallNatRule](request)
+	err := c.client.GetWithContext(ctx, iaasBasePath+"/virtualfirewall/"+firewallId+"/natrules", page, &request)
+	return page, err
+}
 
-	return page, rules, err
+func (c *VirtualFirewallServiceImpl) AllNatRules(ctx context.Context, firewallId string, request PageRequest) iter.Seq2[VirtualFirewallNatRule, error] {
+	return paginate(ctx, request, func(ctx context.Context, request PageRequest) (*Page[VirtualFirewallNatRule], error) {
+		return c.PageNatRulesWithContext(ctx, firewallId, request)
+	})
 }
 
 func (c *VirtualFirewallServiceImpl) CreateNatRule(firewallId string, create VirtualFirewallNatRuleCreate) (*Reference, error) {
+	return c.CreateNatRuleWithContext(context.Background(), firewallId, create)
+}
+
+func (c *VirtualFirewallServiceImpl) CreateNatRuleWithContext(ctx context.Context, firewallId string, create VirtualFirewallNatRuleCreate) (*Reference, error) {
 	response := new(Reference)
-	err := c.client.Post(iaasBasePath+"/virtualfirewall/"+firewallId+"/natrules", create, &response)
+	err := c.client.PostWithContext(ctx, iaasBasePath+"/virtualfirewall/"+firewallId+"/natrules", create, &response)
 	return response, err
 }
 
 func (c *VirtualFirewallServiceImpl) UpdateNatRule(firewallId string, id string, create VirtualFirewallNatRuleCreate) error {
-	err := c.client.Put(iaasBasePath+"/virtualfirewall/"+firewallId+"/natrules/"+id, create, nil)
+	return c.UpdateNatRuleWithContext(context.Background(), firewallId, id, create)
+}
+
+func (c *VirtualFirewallServiceImpl) UpdateNatRuleWithContext(ctx context.Context, firewallId string, id string, create VirtualFirewallNatRuleCreate) error {
+	err := c.client.PutWithContext(ctx, iaasBasePath+"/virtualfirewall/"+firewallId+"/natrules/"+id, create, nil)
 	return err
 }
 
 func (c *VirtualFirewallServiceImpl) DeleteNatRule(firewallId string, id string) error {
-	err := c.client.Delete(iaasBasePath+"/virtualfirewall/"+firewallId+"/natrules/"+id, nil)
+	return c.DeleteNatRuleWithContext(context.Background(), firewallId, id)
+}
+
+func (c *VirtualFirewallServiceImpl) DeleteNatRuleWithContext(ctx context.Context, firewallId string, id string) error {
+	err := c.client.DeleteWithContext(ctx, iaasBasePath+"/virtualfirewall/"+firewallId+"/natrules/"+id, nil)
 	return err
 }
+
+// WaitForState polls the virtual firewall until it reaches the state or one of the failure states
+func (c *VirtualFirewallServiceImpl) WaitForState(ctx context.Context, id string, state string, options WaitOptions) (*VirtualFirewallExt, error) {
+	return waitForState(ctx, id, state, options, c.GetWithContext, func(firewall *VirtualFirewallExt) string {
+		return firewall.State
+	})
+}
@@ -1,6 +1,9 @@
 package client
 
-import "encoding/json"
+import (
+	"context"
+	"iter"
+)
 
 const (
 	VirtualNetworkStateNew   = "NEW"
@@ -8,11 +11,19 @@ const (
 )
 
 type VirtualNetworkService interface {
-	Page(request PageRequest) (*Page, *[]VirtualNetwork, error)
+	Page(request PageRequest) (*Page[VirtualNetwork], error)
+	PageWithContext(ctx context.Context, request PageRequest) (*Page[VirtualNetwork], error)
+	All(ctx context.Context, request PageRequest) iter.Seq2[VirtualNetwork, error]
 	Get(id string) (*VirtualNetwork, error)
-	Create(vn *VirtualNetworkUpdate) (*VirtualNetworkTask, error)
-	Delete(id string) (*VirtualNetworkTask, error)
-	Update(id string, vn *VirtualNetworkUpdate) (*VirtualNetworkTask, error)
+	GetWithContext(ctx context.Context, id string) (*VirtualNetwork, error)
+	Create(vn *VirtualNetworkUpdate) (*Operation[*VirtualNetwork], error)
+	CreateWithContext(ctx context.Context, vn *VirtualNetworkUpdate) (*Operation[*VirtualNetwork], error)
+	Delete(id string) (*Operation[*VirtualNetwork], error)
+	DeleteWithContext(ctx context.Context, id string) (*Operation[*VirtualNetwork], error)
+	Update(id string, vn *VirtualNetworkUpdate) (*Operation[*VirtualNetwork], error)
+	UpdateWithContext(ctx context.Context, id string, vn *VirtualNetworkUpdate) (*Operation[*VirtualNetwork], error)
+	WaitForState(ctx context.Context, id string, state string, options WaitOptions) (*VirtualNetwork, error)
+	WaitUntilReady(ctx context.Context, id string, options WaitOptions) (*VirtualNetwork, error)
 }
 
 type VirtualNetworkServiceImpl struct {
@@ -21,7 +32,6 @@ type VirtualNetworkServiceImpl struct {
 
 type VirtualNetworkTask struct {
 	Task
-	VirtualNetwork     string
 	VirtualNetworkName string
 }
 
@@ -41,41 +51,76 @@ type VirtualNetworkUpdate struct {
 	Group string `json:"group,omitempty"`
 }
 
-func (c *VirtualNetworkServiceImpl) Page(request PageRequest) (*Page, *[]VirtualNetwork, error) {
-	page := new(Page)
-	err := c.client.Get(iaasBasePath+"virtualnetwork", page, &request)
-	if err != nil {
-		return nil, nil, err
-	}
+func (c *VirtualNetworkServiceImpl) Page(request PageRequest) (*Page[VirtualNetwork], error) {
+	return c.PageWithContext(context.Background(), request)
+}
 
-	virtualNetworks := new([]VirtualNetwork)
-	if err := json.Unmarshal(page.Content, &virtualNetworks); err != nil {
-		return nil, nil, err
-	}
+func (c *VirtualNetworkServiceImpl) PageWithContext(ctx context.Context, request PageRequest) (*Page[VirtualNetwork], error) {
+	page := newPage[VirtualNetwork](request)
+	err := c.client.GetWithContext(ctx, iaasBasePath+"virtualnetwork", page, &request)
+	return page, err
+}
 
-	return page, virtualNetworks, err
+func (c *VirtualNetworkServiceImpl) All(ctx context.Context, request PageRequest) iter.Seq2[VirtualNetwork, error] {
+	return paginate(ctx, request, c.PageWithContext)
 }
 
 func (c *VirtualNetworkServiceImpl) Get(id string) (*VirtualNetwork, error) {
+	return c.GetWithContext(context.Background(), id)
+}
+
+func (c *VirtualNetworkServiceImpl) GetWithContext(ctx context.Context, id string) (*VirtualNetwork, error) {
 	virtualNetwork := new(VirtualNetwork)
-	err := c.client.Get(iaasBasePath+"virtualnetwork/"+id, virtualNetwork, nil)
+	err := c.client.GetWithContext(ctx, iaasBasePath+"virtualnetwork/"+id, virtualNetwork, nil)
 	return virtualNetwork, err
 }
 
-func (c *VirtualNetworkServiceImpl) Create(vn *VirtualNetworkUpdate) (*VirtualNetworkTask, error) {
+func (c *VirtualNetworkServiceImpl) Create(vn *VirtualNetworkUpdate) (*Operation[*VirtualNetwork], error) {
+	return c.CreateWithContext(context.Background(), vn)
+}
+
+func (c *VirtualNetworkServiceImpl) CreateWithContext(ctx context.Context, vn *VirtualNetworkUpdate) (*Operation[*VirtualNetwork], error) {
 	task := new(VirtualNetworkTask)
-	err := c.client.Post(iaasBasePath+"virtualnetwork", vn, task)
-	return task, err
+	err := c.client.PostWithContext(ctx, iaasBasePath+"virtualnetwork", vn, task)
+	if err != nil {
+		return nil, err
+	}
+	return newTaskOperation(c.client.Task, task.VirtualNetwork, &task.Task, c.GetWithContext), nil
 }
 
-func (c *VirtualNetworkServiceImpl) Update(id string, vn *VirtualNetworkUpdate) (*VirtualNetworkTask, error) {
+func (c *VirtualNetworkServiceImpl) Update(id string, vn *VirtualNetworkUpdate) (*Operation[*VirtualNetwork], error) {
+	return c.UpdateWithContext(context.Background(), id, vn)
+}
+
+func (c *VirtualNetworkServiceImpl) UpdateWithContext(ctx context.Context, id string, vn *VirtualNetworkUpdate) (*Operation[*VirtualNetwork], error) {
 	task := new(VirtualNetworkTask)
-	err := c.client.Put(iaasBasePath+"virtualnetwork/"+id, vn, task)
-	return task, err
+	err := c.client.PutWithContext(ctx, iaasBasePath+"virtualnetwork/"+id, vn, task)
+	if err != nil {
+		return nil, err
+	}
+	return newTaskOperation(c.client.Task, id, &task.Task, c.GetWithContext), nil
+}
+
+func (c *VirtualNetworkServiceImpl) Delete(id string) (*Operation[*VirtualNetwork], error) {
+	return c.DeleteWithContext(context.Background(), id)
 }
 
-func (c *VirtualNetworkServiceImpl) Delete(id string) (*VirtualNetworkTask, error) {
+func (c *VirtualNetworkServiceImpl) DeleteWithContext(ctx context.Context, id string) (*Operation[*VirtualNetwork], error) {
 	task := new(VirtualNetworkTask)
-	err := c.client.Delete(iaasBasePath+"virtualnetwork/"+id, task)
-	return task, err
+	err := c.client.DeleteWithContext(ctx, iaasBasePath+"virtualnetwork/"+id, task)
+	if err != nil {
+		return nil, err
+	}
+	return newTaskOperation[*VirtualNetwork](c.client.Task, id, &task.Task, nil), nil
+}
+
+// WaitForState polls the virtual network until it reaches the state or one of the failure states
+func (c *VirtualNetworkServiceImpl) WaitForState(ctx context.Context, id string, state string, options WaitOptions) (*VirtualNetwork, error) {
+	return waitForState(ctx, id, state, options, c.GetWithContext, func(vn *VirtualNetwork) string {
+		return vn.State
+	})
+}
+
+func (c *VirtualNetworkServiceImpl) WaitUntilReady(ctx context.Context, id string, options WaitOptions) (*VirtualNetwork, error) {
+	return c.WaitForState(ctx, id, VirtualNetworkStateReady, options)
 }
@@ -1,34 +1,85 @@
 package client
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"fmt"
+	"iter"
 )
 
+// VmAction is a power action of a virtual machine
+type VmAction string
+
 // noinspection GoUnusedConst
 const (
-	VmActionPowerOn  = "POWERON"
-	VmActionPowerOff = "POWEROFF"
-	VmActionShutdown = "SHUTDOWN"
-	VmActionReboot   = "REBOOT"
-	VmActionSuspend  = "SUSPEND"
-	VmActionReset    = "RESET"
+	VmActionPowerOn  VmAction = "POWERON"
+	VmActionPowerOff VmAction = "POWEROFF"
+	VmActionShutdown VmAction = "SHUTDOWN"
+	VmActionReboot   VmAction = "REBOOT"
+	VmActionSuspend  VmAction = "SUSPEND"
+	VmActionReset    VmAction = "RESET"
 
 	VmStateNew        = "NEW"
 	VmStateDeploying  = "DEPLOYING"
 	VmStatePoweredOff = "POWEREDOFF"
 	VmStatePoweredOn  = "POWEREDON"
+	VmStateSuspended  = "SUSPENDED"
 )
 
+var ErrInvalidVmAction = errors.New("invalid virtual machine action")
+
+// ExpectedState is the state of the virtual machine after the action, empty for invalid actions
+func (a VmAction) ExpectedState() string {
+	switch a {
+	case VmActionPowerOn, VmActionReboot, VmActionReset:
+		return VmStatePoweredOn
+	case VmActionPowerOff, VmActionShutdown:
+		return VmStatePoweredOff
+	case VmActionSuspend:
+		return VmStateSuspended
+	}
+	return ""
+}
+
+func (a VmAction) Validate() error {
+	if a.ExpectedState() == "" {
+		return fmt.Errorf("%w: %q", ErrInvalidVmAction, string(a))
+	}
+	return nil
+}
+
 type VirtualServerService interface {
 	ComputeClusterList() (*[]ComputeCluster, error)
+	ComputeClusterListWithContext(ctx context.Context) (*[]ComputeCluster, error)
 	VirtualMachineTemplateList() (*[]VirtualMachineTemplate, error)
-	Page(request PageRequest) (*Page, *[]VirtualMachine, error)
+	VirtualMachineTemplateListWithContext(ctx context.Context) (*[]VirtualMachineTemplate, error)
+	Page(request PageRequest) (*Page[VirtualMachine], error)
+	PageWithContext(ctx context.Context, request PageRequest) (*Page[VirtualMachine], error)
+	All(ctx context.Context, request PageRequest) iter.Seq2[VirtualMachine, error]
 	Get(id string) (*VirtualMachineExt, error)
-	Create(vm *VirtualMachineCreate) (*VirtualMachineTask, error)
-	Delete(id string) (*VirtualMachineTask, error)
-	Update(id string, vm *VirtualMachineUpdate) (*VirtualMachineTask, error)
-	Control(id string, action string) (*VirtualMachineTask, error)
+	GetWithContext(ctx context.Context, id string) (*VirtualMachineExt, error)
+	Create(vm *VirtualMachineCreate) (*Operation[*VirtualMachineExt], error)
+	CreateWithContext(ctx context.Context, vm *VirtualMachineCreate) (*Operation[*VirtualMachineExt], error)
+	Delete(id string) (*Operation[*VirtualMachineExt], error)
+	DeleteWithContext(ctx context.Context, id string) (*Operation[*VirtualMachineExt], error)
+	Update(id string, vm *VirtualMachineUpdate) (*Operation[*VirtualMachineExt], error)
+	UpdateWithContext(ctx context.Context, id string, vm *VirtualMachineUpdate) (*Operation[*VirtualMachineExt], error)
+	Control(id string, action VmAction) (*VirtualMachineTask, error)
+	ControlWithContext(ctx context.Context, id string, action VmAction) (*VirtualMachineTask, error)
+	PowerOn(id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error)
+	PowerOnWithContext(ctx context.Context, id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error)
+	PowerOff(id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error)
+	PowerOffWithContext(ctx context.Context, id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error)
+	Shutdown(id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error)
+	ShutdownWithContext(ctx context.Context, id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error)
+	Reboot(id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error)
+	RebootWithContext(ctx context.Context, id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error)
+	Suspend(id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error)
+	SuspendWithContext(ctx context.Context, id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error)
+	Reset(id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error)
+	ResetWithContext(ctx context.Context, id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error)
 	OpenConsole(id string) (*OpenConsoleResult, error)
+	OpenConsoleWithContext(ctx context.Context, id string) (*OpenConsoleResult, error)
 }
 
 type VirtualServerServiceImpl struct {
@@ -37,7 +88,6 @@ type VirtualServerServiceImpl struct {
 
 type VirtualMachineTask struct {
 	Task
-	VirtualMachine     string
 	VirtualMachineName string
 }
 
@@ -148,64 +198,190 @@ type OpenConsoleResult struct {
 }
 
 func (c *VirtualServerServiceImpl) ComputeClusterList() (*[]ComputeCluster, error) {
+	return c.ComputeClusterListWithContext(context.Background())
+}
+
+func (c *VirtualServerServiceImpl) ComputeClusterListWithContext(ctx context.Context) (*[]ComputeCluster, error) {
 	computeClusters := new([]ComputeCluster)
-	err := c.client.Get(iaasBasePath+"computecluster", computeClusters, nil)
+	err := c.client.GetWithContext(ctx, iaasBasePath+"computecluster", computeClusters, nil)
 	return computeClusters, err
 }
 
 func (c *VirtualServerServiceImpl) VirtualMachineTemplateList() (*[]VirtualMachineTemplate, error) {
+	return c.VirtualMachineTemplateListWithContext(context.Background())
+}
+
+func (c *VirtualServerServiceImpl) VirtualMachineTemplateListWithContext(ctx context.Context) (*[]VirtualMachineTemplate, error) {
 	virtualMachineTemplates := new([]VirtualMachineTemplate)
-	err := c.client.Get(iaasBasePath+"template", virtualMachineTemplates, nil)
+	err := c.client.GetWithContext(ctx, iaasBasePath+"template", virtualMachineTemplates, nil)
 	return virtualMachineTemplates, err
 }
 
-func (c *VirtualServerServiceImpl) Page(request PageRequest) (*Page, *[]VirtualMachine, error) {
-	page := new(Page)
-	err := c.client.Get(iaasBasePath+"virtualmachine", page, &request)
-	if err != nil {
-		return nil, nil, err
-	}
+func (c *VirtualServerServiceImpl) Page(request PageRequest) (*Page[VirtualMachine], error) {
+	return c.PageWithContext(context.Background(), request)
+}
 
-	virtualMachines := new([]VirtualMachine)
-	if err := json.Unmarshal(page.Content, &virtualMachines); err != nil {
-		return nil, nil, err
-	}
+func (c *VirtualServerServiceImpl) PageWithContext(ctx context.Context, request PageRequest) (*Page[VirtualMachine], error) {
+	page := newPage[VirtualMachine](request)
+	err := c.client.GetWithContext(ctx, iaasBasePath+"virtualmachine", page, &request)
+	return page, err
+}
 
-	return page, virtualMachines, err
+func (c *VirtualServerServiceImpl) All(ctx context.Context, request PageRequest) iter.Seq2[VirtualMachine, error] {
+	return paginate(ctx, request, c.PageWithContext)
 }
 
 func (c *VirtualServerServiceImpl) Get(id string) (*VirtualMachineExt, error) {
+	return c.GetWithContext(context.Background(), id)
+}
+
+func (c *VirtualServerServiceImpl) GetWithContext(ctx context.Context, id string) (*VirtualMachineExt, error) {
 	virtualMachine := new(VirtualMachineExt)
-	err := c.client.Get(iaasBasePath+"virtualmachine/"+id, virtualMachine, nil)
+	err := c.client.GetWithContext(ctx, iaasBasePath+"virtualmachine/"+id, virtualMachine, nil)
 	return virtualMachine, err
 }
 
-func (c *VirtualServerServiceImpl) Create(vm *VirtualMachineCreate) (*VirtualMachineTask, error) {
+func (c *VirtualServerServiceImpl) Create(vm *VirtualMachineCreate) (*Operation[*VirtualMachineExt], error) {
+	return c.CreateWithContext(context.Background(), vm)
+}
+
+func (c *VirtualServerServiceImpl) CreateWithContext(ctx context.Context, vm *VirtualMachineCreate) (*Operation[*VirtualMachineExt], error) {
 	task := new(VirtualMachineTask)
-	err := c.client.Post(iaasBasePath+"virtualmachine", vm, task)
-	return task, err
+	err := c.client.PostWithContext(ctx, iaasBasePath+"virtualmachine", vm, task)
+	if err != nil {
+		return nil, err
+	}
+	return newTaskOperation(c.client.Task, task.VirtualMachine, &task.Task, c.GetWithContext), nil
+}
+
+func (c *VirtualServerServiceImpl) Update(id string, vm *VirtualMachineUpdate) (*Operation[*VirtualMachineExt], error) {
+	return c.UpdateWithContext(context.Background(), id, vm)
 }
 
-func (c *VirtualServerServiceImpl) Update(id string, vm *VirtualMachineUpdate) (*VirtualMachineTask, error) {
+func (c *VirtualServerServiceImpl) UpdateWithContext(ctx context.Context, id string, vm *VirtualMachineUpdate) (*Operation[*VirtualMachineExt], error) {
 	task := new(VirtualMachineTask)
-	err := c.client.Put(iaasBasePath+"virtualmachine/"+id, vm, task)
-	return task, err
+	err := c.client.PutWithContext(ctx, iaasBasePath+"virtualmachine/"+id, vm, task)
+	if err != nil {
+		return nil, err
+	}
+	return newTaskOperation(c.client.Task, id, &task.Task, c.GetWithContext), nil
+}
+
+func (c *VirtualServerServiceImpl) Delete(id string) (*Operation[*VirtualMachineExt], error) {
+	return c.DeleteWithContext(context.Background(), id)
 }
 
-func (c *VirtualServerServiceImpl) Delete(id string) (*VirtualMachineTask, error) {
+func (c *VirtualServerServiceImpl) DeleteWithContext(ctx context.Context, id string) (*Operation[*VirtualMachineExt], error) {
 	task := new(VirtualMachineTask)
-	err := c.client.Delete(iaasBasePath+"virtualmachine/"+id, task)
-	return task, err
+	err := c.client.DeleteWithContext(ctx, iaasBasePath+"virtualmachine/"+id, task)
+	if err != nil {
+		return nil, err
+	}
+	return newTaskOperation[*VirtualMachineExt](c.client.Task, id, &task.Task, nil), nil
 }
 
-func (c *VirtualServerServiceImpl) Control(id string, action string) (*VirtualMachineTask, error) {
+func (c *VirtualServerServiceImpl) Control(id string, action VmAction) (*VirtualMachineTask, error) {
+	return c.ControlWithContext(context.Background(), id, action)
+}
+
+func (c *VirtualServerServiceImpl) ControlWithContext(ctx context.Context, id string, action VmAction) (*VirtualMachineTask, error) {
+	if err := action.Validate(); err != nil {
+		return nil, err
+	}
 	task := new(VirtualMachineTask)
-	err := c.client.Post(iaasBasePath+"virtualmachine/"+id+"/action/"+action, nil, task)
+	err := c.client.PostWithContext(ctx, iaasBasePath+"virtualmachine/"+id+"/action/"+string(action), nil, task)
 	return task, err
 }
 
+// PowerOn powers on the virtual machine. With wait options it returns after the task
+// completed and the virtual machine reached VmStatePoweredOn, otherwise right away.
+func (c *VirtualServerServiceImpl) PowerOn(id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error) {
+	return c.PowerOnWithContext(context.Background(), id, wait)
+}
+
+func (c *VirtualServerServiceImpl) PowerOnWithContext(ctx context.Context, id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error) {
+	return c.powerAction(ctx, id, VmActionPowerOn, wait)
+}
+
+// PowerOff turns off the virtual machine without shutting down the guest
+func (c *VirtualServerServiceImpl) PowerOff(id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error) {
+	return c.PowerOffWithContext(context.Background(), id, wait)
+}
+
+func (c *VirtualServerServiceImpl) PowerOffWithContext(ctx context.Context, id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error) {
+	return c.powerAction(ctx, id, VmActionPowerOff, wait)
+}
+
+// Shutdown shuts down the guest operating system
+func (c *VirtualServerServiceImpl) Shutdown(id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error) {
+	return c.ShutdownWithContext(context.Background(), id, wait)
+}
+
+func (c *VirtualServerServiceImpl) ShutdownWithContext(ctx context.Context, id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error) {
+	return c.powerAction(ctx, id, VmActionShutdown, wait)
+}
+
+// Reboot restarts the guest operating system
+func (c *VirtualServerServiceImpl) Reboot(id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error) {
+	return c.RebootWithContext(context.Background(), id, wait)
+}
+
+func (c *VirtualServerServiceImpl) RebootWithContext(ctx context.Context, id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error) {
+	return c.powerAction(ctx, id, VmActionReboot, wait)
+}
+
+// Suspend pauses the virtual machine, keeping its memory
+func (c *VirtualServerServiceImpl) Suspend(id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error) {
+	return c.SuspendWithContext(context.Background(), id, wait)
+}
+
+func (c *VirtualServerServiceImpl) SuspendWithContext(ctx context.Context, id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error) {
+	return c.powerAction(ctx, id, VmActionSuspend, wait)
+}
+
+// Reset restarts the virtual machine without shutting down the guest
+func (c *VirtualServerServiceImpl) Reset(id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error) {
+	return c.ResetWithContext(context.Background(), id, wait)
+}
+
+func (c *VirtualServerServiceImpl) ResetWithContext(ctx context.Context, id string, wait *WaitOptions) (*Operation[*VirtualMachineExt], error) {
+	return c.powerAction(ctx, id, VmActionReset, wait)
+}
+
+// powerAction runs the action as an operation that completes when the task completed
+// and the virtual machine reached the expected state
+func (c *VirtualServerServiceImpl) powerAction(ctx context.Context, id string, action VmAction, wait *WaitOptions) (*Operation[*VirtualMachineExt], error) {
+	task, err := c.ControlWithContext(ctx, id, action)
+	if err != nil {
+		return nil, err
+	}
+
+	operation := newOperation(id, &task.Task, func(ctx context.Context, options WaitOptions) (*VirtualMachineExt, error) {
+		// the timeout covers both the task and the state
+		ctx, cancel := newPoller(options).context(ctx)
+		defer cancel()
+		options.Timeout = 0
+		if _, err := c.client.Task.WaitForWithOptions(ctx, task.Id, options); err != nil {
+			return nil, err
+		}
+		return waitForState(ctx, id, action.ExpectedState(), options, c.GetWithContext, func(vm *VirtualMachineExt) string {
+			return vm.State
+		})
+	})
+	if wait != nil {
+		if _, err := operation.WaitWithOptions(ctx, *wait); err != nil {
+			return operation, err
+		}
+	}
+	return operation, nil
+}
+
 func (c *VirtualServerServiceImpl) OpenConsole(id string) (*OpenConsoleResult, error) {
+	return c.OpenConsoleWithContext(context.Background(), id)
+}
+
+func (c *VirtualServerServiceImpl) OpenConsoleWithContext(ctx context.Context, id string) (*OpenConsoleResult, error) {
 	res := new(OpenConsoleResult)
-	err := c.client.Post(iaasBasePath+"virtualmachine/"+id+"/console", nil, res)
+	err := c.client.PostWithContext(ctx, iaasBasePath+"virtualmachine/"+id+"/console", nil, res)
 	return res, err
 }
@@ -0,0 +1,203 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// DefaultWaitOptions polls every second at first, backing off to every 15 seconds, for at most DefaultTimeout
+var DefaultWaitOptions = WaitOptions{
+	Timeout:         DefaultTimeout,
+	InitialInterval: time.Second,
+	Multiplier:      1.5,
+	MaxInterval:     15 * time.Second,
+	MaxPollErrors:   3,
+}
+
+// WaitOptions configures how long and how often a task or resource is polled
+type WaitOptions struct {
+	// Timeout limits the total wait, 0 only stops waiting when the context is done
+	Timeout time.Duration
+	// InitialInterval is the delay before the first poll
+	InitialInterval time.Duration
+	// Multiplier grows the interval after every poll, values up to 1 keep the interval fixed
+	Multiplier float64
+	// MaxInterval caps the interval between polls, 0 means no limit
+	MaxInterval time.Duration
+	// MaxPollErrors is the number of consecutive transient poll errors, like
+	// connection failures and 5xx responses, tolerated before the wait fails
+	MaxPollErrors int
+	// FailureStates end the wait for a resource state with a *StateFailedError
+	FailureStates []string
+	// ReadyState is the state an Operation tracked by the resource state waits for. The
+	// API does not document the states of Kubernetes clusters, STaaS environments and
+	// virtual firewalls, so waiting for their operations requires it.
+	ReadyState string
+}
+
+// ErrNoReadyState is returned by the wait of an Operation tracked by the resource state without WaitOptions.ReadyState
+var ErrNoReadyState = errors.New("no ready state to wait for")
+
+// StateFailedError is returned when a resource reached a failure state instead of the state waited for
+type StateFailedError struct {
+	Id    string
+	State string
+	Want  string
+}
+
+func (e *StateFailedError) Error() string {
+	return fmt.Sprintf("resource %s reached state %s while waiting for state %s", e.Id, e.State, e.Want)
+}
+
+// StateTimeoutError is returned when the wait for a resource state timed out or its context was canceled
+type StateTimeoutError struct {
+	Id string
+	// State is the last observed state, empty if the resource was never retrieved
+	State string
+	Want  string
+	Err   error
+}
+
+func (e *StateTimeoutError) Error() string {
+	return fmt.Sprintf("waiting for resource %s to reach state %s: %v", e.Id, e.Want, e.Err)
+}
+
+func (e *StateTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// waitForDeletion polls the resource until it is not found anymore
+func waitForDeletion[T any](ctx context.Context, id string, options WaitOptions, get func(ctx context.Context, id string) (T, error)) (T, error) {
+	var zero T
+	p := newPoller(options)
+	ctx, cancel := p.context(ctx)
+	defer cancel()
+	for {
+		_, err := get(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			return zero, nil
+		}
+		if err != nil && ctx.Err() != nil {
+			return zero, &StateTimeoutError{Id: id, Want: "deleted", Err: ctx.Err()}
+		}
+		if !p.tolerate(err) {
+			return zero, err
+		}
+		if err := p.wait(ctx); err != nil {
+			return zero, &StateTimeoutError{Id: id, Want: "deleted", Err: err}
+		}
+	}
+}
+
+// waitForState polls the resource until it reaches the wanted state, returning a *StateFailedError
+// on a failure state and a *StateTimeoutError when the wait ended first
+func waitForState[T any](ctx context.Context, id string, want string, options WaitOptions,
+	get func(ctx context.Context, id string) (T, error), state func(T) string) (T, error) {
+	return pollState(ctx, id, want, options, get, state, func(resource T) bool {
+		return state(resource) == want
+	})
+}
+
+// waitForUpdate polls the resource until modified reports the update as applied and, when the
+// options have a ready state, the resource is in that state
+func waitForUpdate[T any](ctx context.Context, id string, options WaitOptions,
+	get func(ctx context.Context, id string) (T, error), state func(T) string, modified func(T) bool) (T, error) {
+	want := options.ReadyState
+	if want == "" {
+		want = "updated"
+	}
+	return pollState(ctx, id, want, options, get, state, func(resource T) bool {
+		return modified(resource) && (options.ReadyState == "" || state(resource) == options.ReadyState)
+	})
+}
+
+// pollState polls the resource until done reports true, returning a *StateFailedError
+// on a failure state and a *StateTimeoutError when the wait ended first
+func pollState[T any](ctx context.Context, id string, want string, options WaitOptions,
+	get func(ctx context.Context, id string) (T, error), state func(T) string, done func(T) bool) (T, error) {
+	p := newPoller(options)
+	ctx, cancel := p.context(ctx)
+	defer cancel()
+	var last T
+	observed := ""
+	for {
+		resource, err := get(ctx, id)
+		if err != nil && ctx.Err() != nil {
+			return last, &StateTimeoutError{Id: id, State: observed, Want: want, Err: ctx.Err()}
+		}
+		if !p.tolerate(err) {
+			return last, err
+		}
+		if err == nil {
+			last, observed = resource, state(resource)
+			if done(resource) {
+				return resource, nil
+			}
+			if slices.Contains(options.FailureStates, observed) {
+				return resource, &StateFailedError{Id: id, State: observed, Want: want}
+			}
+		}
+		if err := p.wait(ctx); err != nil {
+			return last, &StateTimeoutError{Id: id, State: observed, Want: want, Err: err}
+		}
+	}
+}
+
+// poller keeps the interval and consecutive errors of a polling loop
+type poller struct {
+	options  WaitOptions
+	interval time.Duration
+	errors   int
+}
+
+func newPoller(options WaitOptions) *poller {
+	interval := options.InitialInterval
+	if interval <= 0 {
+		interval = DefaultWaitOptions.InitialInterval
+	}
+	return &poller{options: options, interval: interval}
+}
+
+// context applies the timeout of the options to the context
+func (p *poller) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if p.options.Timeout > 0 {
+		return context.WithTimeout(ctx, p.options.Timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// wait sleeps for the current interval and grows the interval for the next poll
+func (p *poller) wait(ctx context.Context) error {
+	if err := sleep(ctx, p.interval); err != nil {
+		return err
+	}
+	if p.options.Multiplier > 1 {
+		p.interval = time.Duration(float64(p.interval) * p.options.Multiplier)
+	}
+	if p.options.MaxInterval > 0 && p.interval > p.options.MaxInterval {
+		p.interval = p.options.MaxInterval
+	}
+	return nil
+}
+
+// tolerate reports whether a failed poll can be ignored, resetting the error count on success
+func (p *poller) tolerate(err error) bool {
+	if err == nil {
+		p.errors = 0
+		return true
+	}
+	p.errors++
+	return p.errors <= p.options.MaxPollErrors && transient(err)
+}
+
+// transient reports whether an error is likely to go away on a next poll
+func transient(err error) bool {
+	var apiError *ApiError
+	if errors.As(err, &apiError) {
+		return retryableStatus(apiError.Code)
+	}
+	return shouldRetry(nil, err)
+}
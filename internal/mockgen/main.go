@@ -0,0 +1,318 @@
+// Command mockgen generates the test doubles in the mocks package for every
+// Service interface of the client package.
+//
+//	go run ./internal/mockgen -source client -output mocks/mocks_gen.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const clientImport = "github.com/previder/previder-go-sdk/client"
+
+type method struct {
+	name     string
+	params   []param
+	results  []string
+	variadic bool
+}
+
+type param struct {
+	name string
+	typ  string
+}
+
+type generator struct {
+	fset    *token.FileSet
+	imports map[string]string
+}
+
+func main() {
+	source := flag.String("source", "client", "directory of the client package")
+	output := flag.String("output", "mocks/mocks_gen.go", "file to write the mocks to")
+	flag.Parse()
+
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, *source, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+	pkg, ok := pkgs["client"]
+	if !ok {
+		log.Fatalf("no client package in %s", *source)
+	}
+
+	g := &generator{fset: fset, imports: map[string]string{"client": clientImport}}
+	interfaces := map[string][]method{}
+	for _, file := range pkg.Files {
+		fileImports := map[string]string{}
+		for _, spec := range file.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			name := path.Base(importPath)
+			if spec.Name != nil {
+				name = spec.Name.Name
+			}
+			fileImports[name] = importPath
+		}
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				iface, ok := typeSpec.Type.(*ast.InterfaceType)
+				if !ok || !strings.HasSuffix(typeSpec.Name.Name, "Service") {
+					continue
+				}
+				interfaces[typeSpec.Name.Name] = g.methods(iface, fileImports)
+			}
+		}
+	}
+
+	src, err := format.Source(g.render(interfaces))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (g *generator) methods(iface *ast.InterfaceType, fileImports map[string]string) []method {
+	var methods []method
+	for _, field := range iface.Methods.List {
+		funcType, ok := field.Type.(*ast.FuncType)
+		if !ok {
+			log.Fatalf("embedded interfaces are not supported: %s", g.expr(field.Type, fileImports))
+		}
+		m := method{name: field.Names[0].Name}
+		for _, p := range funcType.Params.List {
+			typ := p.Type
+			if ellipsis, ok := typ.(*ast.Ellipsis); ok {
+				m.variadic = true
+				typ = ellipsis.Elt
+			}
+			names := p.Names
+			if len(names) == 0 {
+				names = []*ast.Ident{{Name: "_"}}
+			}
+			for _, name := range names {
+				paramName := name.Name
+				switch paramName {
+				case "_":
+					paramName = fmt.Sprintf("p%d", len(m.params))
+				case "m", "results", "result":
+					// reserved in the generated method bodies
+					paramName += "Arg"
+				}
+				m.params = append(m.params, param{name: paramName, typ: g.expr(typ, fileImports)})
+			}
+		}
+		if funcType.Results != nil {
+			for _, r := range funcType.Results.List {
+				for range max(len(r.Names), 1) {
+					m.results = append(m.results, g.expr(r.Type, fileImports))
+				}
+			}
+		}
+		methods = append(methods, m)
+	}
+	return methods
+}
+
+// expr renders a type expression as seen from the mocks package, qualifying client types
+func (g *generator) expr(e ast.Expr, fileImports map[string]string) string {
+	switch t := e.(type) {
+	case *ast.Ident:
+		if ast.IsExported(t.Name) {
+			return "client." + t.Name
+		}
+		return t.Name
+	case *ast.StarExpr:
+		return "*" + g.expr(t.X, fileImports)
+	case *ast.ArrayType:
+		return "[]" + g.expr(t.Elt, fileImports)
+	case *ast.MapType:
+		return "map[" + g.expr(t.Key, fileImports) + "]" + g.expr(t.Value, fileImports)
+	case *ast.ChanType:
+		prefix := "chan "
+		switch t.Dir {
+		case ast.RECV:
+			prefix = "<-chan "
+		case ast.SEND:
+			prefix = "chan<- "
+		}
+		return prefix + g.expr(t.Value, fileImports)
+	case *ast.SelectorExpr:
+		pkg := t.X.(*ast.Ident).Name
+		g.imports[pkg] = fileImports[pkg]
+		return pkg + "." + t.Sel.Name
+	case *ast.IndexExpr:
+		return g.expr(t.X, fileImports) + "[" + g.expr(t.Index, fileImports) + "]"
+	case *ast.IndexListExpr:
+		var indices []string
+		for _, index := range t.Indices {
+			indices = append(indices, g.expr(index, fileImports))
+		}
+		return g.expr(t.X, fileImports) + "[" + strings.Join(indices, ", ") + "]"
+	case *ast.FuncType:
+		var params, results []string
+		for _, p := range t.Params.List {
+			for range max(len(p.Names), 1) {
+				params = append(params, g.expr(p.Type, fileImports))
+			}
+		}
+		if t.Results != nil {
+			for _, r := range t.Results.List {
+				for range max(len(r.Names), 1) {
+					results = append(results, g.expr(r.Type, fileImports))
+				}
+			}
+		}
+		return "func(" + strings.Join(params, ", ") + ")" + resultList(results)
+	case *ast.InterfaceType:
+		if len(t.Methods.List) == 0 {
+			return "interface{}"
+		}
+	}
+	var b bytes.Buffer
+	_ = format.Node(&b, g.fset, e)
+	log.Fatalf("unsupported type expression %s", b.String())
+	return ""
+}
+
+func (g *generator) render(interfaces map[string][]method) []byte {
+	var names []string
+	for name := range interfaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by internal/mockgen. DO NOT EDIT.\n\npackage mocks\n\nimport (\n")
+	var std, other []string
+	for _, importPath := range g.imports {
+		if strings.Contains(strings.Split(importPath, "/")[0], ".") {
+			other = append(other, importPath)
+		} else {
+			std = append(std, importPath)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+	for _, importPath := range std {
+		fmt.Fprintf(&b, "\t%q\n", importPath)
+	}
+	if len(std) > 0 && len(other) > 0 {
+		b.WriteString("\n")
+	}
+	for _, importPath := range other {
+		fmt.Fprintf(&b, "\t%q\n", importPath)
+	}
+	b.WriteString(")\n")
+
+	for _, name := range names {
+		methods := interfaces[name]
+		fmt.Fprintf(&b, "\n// %s is a test double for client.%s\ntype %s struct {\n\tMock\n", name, name, name)
+		for _, m := range methods {
+			fmt.Fprintf(&b, "\t%sFunc %s\n", m.name, m.funcType())
+		}
+		fmt.Fprintf(&b, "}\n\nvar _ client.%s = (*%s)(nil)\n", name, name)
+
+		for _, m := range methods {
+			var params, args []string
+			for i, p := range m.params {
+				typ := p.typ
+				arg := p.name
+				if m.variadic && i == len(m.params)-1 {
+					typ = "..." + typ
+					arg += "..."
+				}
+				params = append(params, p.name+" "+typ)
+				args = append(args, arg)
+			}
+			var recorded []string
+			for _, p := range m.params {
+				recorded = append(recorded, p.name)
+			}
+
+			fmt.Fprintf(&b, "\nfunc (m *%s) %s(%s)%s {\n", name, m.name, strings.Join(params, ", "), resultList(m.results))
+			resultsVar := "_"
+			if len(m.results) > 0 {
+				resultsVar = "results"
+			}
+			fmt.Fprintf(&b, "\t%s := m.Called(%s)\n", resultsVar, strings.Join(append([]string{strconv.Quote(m.name)}, recorded...), ", "))
+			fmt.Fprintf(&b, "\tif m.%sFunc != nil {\n", m.name)
+			if len(m.results) > 0 {
+				fmt.Fprintf(&b, "\t\treturn m.%sFunc(%s)\n\t}\n", m.name, strings.Join(args, ", "))
+			} else {
+				fmt.Fprintf(&b, "\t\tm.%sFunc(%s)\n\t}\n", m.name, strings.Join(args, ", "))
+			}
+			if len(m.results) == 0 {
+				b.WriteString("}\n")
+				continue
+			}
+			var returns []string
+			for i, r := range m.results {
+				if empty := emptyValue(r); empty != "" {
+					fmt.Fprintf(&b, "\tr%d := %s\n", i, empty)
+				} else {
+					fmt.Fprintf(&b, "\tvar r%d %s\n", i, r)
+				}
+				fmt.Fprintf(&b, "\tresult(results, %d, &r%d)\n", i, i)
+				returns = append(returns, fmt.Sprintf("r%d", i))
+			}
+			fmt.Fprintf(&b, "\treturn %s\n}\n", strings.Join(returns, ", "))
+		}
+	}
+	return b.Bytes()
+}
+
+func (m method) funcType() string {
+	var params []string
+	for i, p := range m.params {
+		if m.variadic && i == len(m.params)-1 {
+			params = append(params, "..."+p.typ)
+		} else {
+			params = append(params, p.typ)
+		}
+	}
+	return "func(" + strings.Join(params, ", ") + ")" + resultList(m.results)
+}
+
+func resultList(results []string) string {
+	switch len(results) {
+	case 0:
+		return ""
+	case 1:
+		return " " + results[0]
+	}
+	return " (" + strings.Join(results, ", ") + ")"
+}
+
+// emptyValue is the default of a result type whose zero value cannot be used, as
+// ranging over a nil iterator panics. It is empty for all other types.
+func emptyValue(typ string) string {
+	if args, ok := strings.CutPrefix(typ, "iter.Seq2["); ok {
+		return "emptySeq2[" + args + "()"
+	}
+	if args, ok := strings.CutPrefix(typ, "iter.Seq["); ok {
+		return "emptySeq[" + args + "()"
+	}
+	return ""
+}
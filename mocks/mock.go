@@ -0,0 +1,173 @@
+// Package mocks provides test doubles for every Service interface of the client
+// package. Assign them to the service fields of a client.PreviderClient:
+//
+//	virtualServer := &mocks.VirtualServerService{}
+//	virtualServer.On("Get", &client.VirtualMachineExt{VirtualMachine: client.VirtualMachine{Name: "web01"}}, nil)
+//	virtualServer.Expect("Get", 1)
+//	previderClient := &client.PreviderClient{VirtualServer: virtualServer}
+//	...
+//	virtualServer.AssertExpectations(t)
+//
+// Every call is recorded. A method returns the result of its XxxFunc field when
+// set, otherwise the canned results given to On, otherwise zero values. Iterators
+// default to an empty sequence instead of nil.
+package mocks
+
+//go:generate go run ../internal/mockgen -source ../client -output mocks_gen.go
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"sync"
+)
+
+// TestingT is the subset of testing.TB used for assertions
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Mock records calls and holds canned results, it is embedded in every test double
+type Mock struct {
+	mu       sync.Mutex
+	calls    []Call
+	results  map[string][][]interface{}
+	expected map[string]int
+}
+
+// On sets the results returned by method. Calling On multiple times for the same
+// method queues the results, the last ones are returned for all remaining calls.
+func (m *Mock) On(method string, results ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.results == nil {
+		m.results = map[string][][]interface{}{}
+	}
+	m.results[method] = append(m.results[method], results)
+}
+
+// Expect registers that method must be called exactly times times, checked by AssertExpectations
+func (m *Mock) Expect(method string, times int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.expected == nil {
+		m.expected = map[string]int{}
+	}
+	m.expected[method] = times
+}
+
+// Called records a call and returns the canned results for it
+func (m *Mock) Called(method string, args ...interface{}) []interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+	queue := m.results[method]
+	if len(queue) == 0 {
+		return nil
+	}
+	results := queue[0]
+	if len(queue) > 1 {
+		m.results[method] = queue[1:]
+	}
+	return results
+}
+
+// Calls returns all recorded calls, or only those to the given methods
+func (m *Mock) Calls(methods ...string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var calls []Call
+	for _, call := range m.calls {
+		if len(methods) == 0 || contains(methods, call.Method) {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func (m *Mock) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+	m.results = nil
+	m.expected = nil
+}
+
+func (m *Mock) AssertCalled(t TestingT, method string, args ...interface{}) bool {
+	t.Helper()
+	for _, call := range m.Calls(method) {
+		if len(args) == 0 || reflect.DeepEqual(call.Args, args) {
+			return true
+		}
+	}
+	if len(args) == 0 {
+		t.Errorf("expected a call to %s", method)
+	} else {
+		t.Errorf("expected a call to %s with arguments %v", method, args)
+	}
+	return false
+}
+
+func (m *Mock) AssertNotCalled(t TestingT, method string) bool {
+	t.Helper()
+	if calls := m.Calls(method); len(calls) > 0 {
+		t.Errorf("expected no calls to %s, got %d", method, len(calls))
+		return false
+	}
+	return true
+}
+
+// AssertExpectations checks the call counts registered with Expect
+func (m *Mock) AssertExpectations(t TestingT) bool {
+	t.Helper()
+	m.mu.Lock()
+	expected := make(map[string]int, len(m.expected))
+	for method, times := range m.expected {
+		expected[method] = times
+	}
+	m.mu.Unlock()
+
+	ok := true
+	for method, times := range expected {
+		if calls := len(m.Calls(method)); calls != times {
+			t.Errorf("expected %d calls to %s, got %d", times, method, calls)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// result assigns the canned result at index i to target when it has a matching type
+func result[T any](results []interface{}, i int, target *T) {
+	if i >= len(results) || results[i] == nil {
+		return
+	}
+	value, ok := results[i].(T)
+	if !ok {
+		panic(fmt.Sprintf("mocks: result %d is a %T, expected %T", i, results[i], *target))
+	}
+	*target = value
+}
+
+func emptySeq[V any]() iter.Seq[V] {
+	return func(yield func(V) bool) {}
+}
+
+func emptySeq2[K any, V any]() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
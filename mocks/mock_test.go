@@ -0,0 +1,22 @@
+package mocks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/previder/previder-go-sdk/client"
+)
+
+func TestIteratorsDefaultToEmpty(t *testing.T) {
+	virtualServer := &VirtualServerService{}
+	for vm, err := range virtualServer.All(context.Background(), client.PageRequest{}) {
+		t.Errorf("expected no results, got %v, %v", vm, err)
+	}
+	tasks := &TaskService{}
+	for result := range tasks.WaitForEach(context.Background(), []string{"1"}, client.DefaultWaitOptions) {
+		t.Errorf("expected no results, got %v", result)
+	}
+	if got := virtualServer.Calls("All"); len(got) != 1 {
+		t.Errorf("expected the call to be recorded, got %v", got)
+	}
+}
@@ -0,0 +1,1737 @@
+// Code generated by internal/mockgen. DO NOT EDIT.
+
+package mocks
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"github.com/previder/previder-go-sdk/client"
+)
+
+// CustomerService is a test double for client.CustomerService
+type CustomerService struct {
+	Mock
+	PageFunc              func(client.PageRequest) (*client.Page[client.Customer], error)
+	PageWithContextFunc   func(context.Context, client.PageRequest) (*client.Page[client.Customer], error)
+	AllFunc               func(context.Context, client.PageRequest) iter.Seq2[client.Customer, error]
+	GetFunc               func(string) (*client.CustomerExt, error)
+	GetWithContextFunc    func(context.Context, string) (*client.CustomerExt, error)
+	CreateFunc            func(client.CustomerCreate) (*client.Customer, error)
+	CreateWithContextFunc func(context.Context, client.CustomerCreate) (*client.Customer, error)
+	DeleteFunc            func(string) error
+	DeleteWithContextFunc func(context.Context, string) error
+	UpdateFunc            func(string, client.CustomerCreate) (*client.Customer, error)
+	UpdateWithContextFunc func(context.Context, string, client.CustomerCreate) (*client.Customer, error)
+}
+
+var _ client.CustomerService = (*CustomerService)(nil)
+
+func (m *CustomerService) Page(request client.PageRequest) (*client.Page[client.Customer], error) {
+	results := m.Called("Page", request)
+	if m.PageFunc != nil {
+		return m.PageFunc(request)
+	}
+	var r0 *client.Page[client.Customer]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *CustomerService) PageWithContext(ctx context.Context, request client.PageRequest) (*client.Page[client.Customer], error) {
+	results := m.Called("PageWithContext", ctx, request)
+	if m.PageWithContextFunc != nil {
+		return m.PageWithContextFunc(ctx, request)
+	}
+	var r0 *client.Page[client.Customer]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *CustomerService) All(ctx context.Context, request client.PageRequest) iter.Seq2[client.Customer, error] {
+	results := m.Called("All", ctx, request)
+	if m.AllFunc != nil {
+		return m.AllFunc(ctx, request)
+	}
+	r0 := emptySeq2[client.Customer, error]()
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *CustomerService) Get(id string) (*client.CustomerExt, error) {
+	results := m.Called("Get", id)
+	if m.GetFunc != nil {
+		return m.GetFunc(id)
+	}
+	var r0 *client.CustomerExt
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *CustomerService) GetWithContext(ctx context.Context, id string) (*client.CustomerExt, error) {
+	results := m.Called("GetWithContext", ctx, id)
+	if m.GetWithContextFunc != nil {
+		return m.GetWithContextFunc(ctx, id)
+	}
+	var r0 *client.CustomerExt
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *CustomerService) Create(customerCreate client.CustomerCreate) (*client.Customer, error) {
+	results := m.Called("Create", customerCreate)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(customerCreate)
+	}
+	var r0 *client.Customer
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *CustomerService) CreateWithContext(ctx context.Context, customerCreate client.CustomerCreate) (*client.Customer, error) {
+	results := m.Called("CreateWithContext", ctx, customerCreate)
+	if m.CreateWithContextFunc != nil {
+		return m.CreateWithContextFunc(ctx, customerCreate)
+	}
+	var r0 *client.Customer
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *CustomerService) Delete(id string) error {
+	results := m.Called("Delete", id)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id)
+	}
+	var r0 error
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *CustomerService) DeleteWithContext(ctx context.Context, id string) error {
+	results := m.Called("DeleteWithContext", ctx, id)
+	if m.DeleteWithContextFunc != nil {
+		return m.DeleteWithContextFunc(ctx, id)
+	}
+	var r0 error
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *CustomerService) Update(id string, customerUpdate client.CustomerCreate) (*client.Customer, error) {
+	results := m.Called("Update", id, customerUpdate)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(id, customerUpdate)
+	}
+	var r0 *client.Customer
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *CustomerService) UpdateWithContext(ctx context.Context, id string, customerUpdate client.CustomerCreate) (*client.Customer, error) {
+	results := m.Called("UpdateWithContext", ctx, id, customerUpdate)
+	if m.UpdateWithContextFunc != nil {
+		return m.UpdateWithContextFunc(ctx, id, customerUpdate)
+	}
+	var r0 *client.Customer
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+// KubernetesClusterService is a test double for client.KubernetesClusterService
+type KubernetesClusterService struct {
+	Mock
+	PageFunc                     func(client.PageRequest) (*client.Page[client.KubernetesCluster], error)
+	PageWithContextFunc          func(context.Context, client.PageRequest) (*client.Page[client.KubernetesCluster], error)
+	AllFunc                      func(context.Context, client.PageRequest) iter.Seq2[client.KubernetesCluster, error]
+	GetFunc                      func(string) (*client.KubernetesClusterExt, error)
+	GetWithContextFunc           func(context.Context, string) (*client.KubernetesClusterExt, error)
+	CreateFunc                   func(client.KubernetesClusterCreate) (*client.Operation[*client.KubernetesClusterExt], error)
+	CreateWithContextFunc        func(context.Context, client.KubernetesClusterCreate) (*client.Operation[*client.KubernetesClusterExt], error)
+	DeleteFunc                   func(string) (*client.Operation[*client.KubernetesClusterExt], error)
+	DeleteWithContextFunc        func(context.Context, string) (*client.Operation[*client.KubernetesClusterExt], error)
+	UpdateFunc                   func(string, client.KubernetesClusterUpdate) (*client.Operation[*client.KubernetesClusterExt], error)
+	UpdateWithContextFunc        func(context.Context, string, client.KubernetesClusterUpdate) (*client.Operation[*client.KubernetesClusterExt], error)
+	GetKubeConfigFunc            func(string, string) (client.KubernetesClusterKubeConfigResponse, error)
+	GetKubeConfigWithContextFunc func(context.Context, string, string) (client.KubernetesClusterKubeConfigResponse, error)
+	GetNodeFunc                  func(string, string) (*client.KubernetesClusterNodeInfo, error)
+	GetNodeWithContextFunc       func(context.Context, string, string) (*client.KubernetesClusterNodeInfo, error)
+	PageNodesFunc                func(string, client.PageRequest) (*client.Page[client.KubernetesClusterNodeInfo], error)
+	PageNodesWithContextFunc     func(context.Context, string, client.PageRequest) (*client.Page[client.KubernetesClusterNodeInfo], error)
+	AllNodesFunc                 func(context.Context, string, client.PageRequest) iter.Seq2[client.KubernetesClusterNodeInfo, error]
+	WaitForStateFunc             func(context.Context, string, string, client.WaitOptions) (*client.KubernetesClusterExt, error)
+}
+
+var _ client.KubernetesClusterService = (*KubernetesClusterService)(nil)
+
+func (m *KubernetesClusterService) Page(request client.PageRequest) (*client.Page[client.KubernetesCluster], error) {
+	results := m.Called("Page", request)
+	if m.PageFunc != nil {
+		return m.PageFunc(request)
+	}
+	var r0 *client.Page[client.KubernetesCluster]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *KubernetesClusterService) PageWithContext(ctx context.Context, request client.PageRequest) (*client.Page[client.KubernetesCluster], error) {
+	results := m.Called("PageWithContext", ctx, request)
+	if m.PageWithContextFunc != nil {
+		return m.PageWithContextFunc(ctx, request)
+	}
+	var r0 *client.Page[client.KubernetesCluster]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *KubernetesClusterService) All(ctx context.Context, request client.PageRequest) iter.Seq2[client.KubernetesCluster, error] {
+	results := m.Called("All", ctx, request)
+	if m.AllFunc != nil {
+		return m.AllFunc(ctx, request)
+	}
+	r0 := emptySeq2[client.KubernetesCluster, error]()
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *KubernetesClusterService) Get(id string) (*client.KubernetesClusterExt, error) {
+	results := m.Called("Get", id)
+	if m.GetFunc != nil {
+		return m.GetFunc(id)
+	}
+	var r0 *client.KubernetesClusterExt
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *KubernetesClusterService) GetWithContext(ctx context.Context, id string) (*client.KubernetesClusterExt, error) {
+	results := m.Called("GetWithContext", ctx, id)
+	if m.GetWithContextFunc != nil {
+		return m.GetWithContextFunc(ctx, id)
+	}
+	var r0 *client.KubernetesClusterExt
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *KubernetesClusterService) Create(create client.KubernetesClusterCreate) (*client.Operation[*client.KubernetesClusterExt], error) {
+	results := m.Called("Create", create)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(create)
+	}
+	var r0 *client.Operation[*client.KubernetesClusterExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *KubernetesClusterService) CreateWithContext(ctx context.Context, create client.KubernetesClusterCreate) (*client.Operation[*client.KubernetesClusterExt], error) {
+	results := m.Called("CreateWithContext", ctx, create)
+	if m.CreateWithContextFunc != nil {
+		return m.CreateWithContextFunc(ctx, create)
+	}
+	var r0 *client.Operation[*client.KubernetesClusterExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *KubernetesClusterService) Delete(id string) (*client.Operation[*client.KubernetesClusterExt], error) {
+	results := m.Called("Delete", id)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id)
+	}
+	var r0 *client.Operation[*client.KubernetesClusterExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *KubernetesClusterService) DeleteWithContext(ctx context.Context, id string) (*client.Operation[*client.KubernetesClusterExt], error) {
+	results := m.Called("DeleteWithContext", ctx, id)
+	if m.DeleteWithContextFunc != nil {
+		return m.DeleteWithContextFunc(ctx, id)
+	}
+	var r0 *client.Operation[*client.KubernetesClusterExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *KubernetesClusterService) Update(id string, update client.KubernetesClusterUpdate) (*client.Operation[*client.KubernetesClusterExt], error) {
+	results := m.Called("Update", id, update)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(id, update)
+	}
+	var r0 *client.Operation[*client.KubernetesClusterExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *KubernetesClusterService) UpdateWithContext(ctx context.Context, id string, update client.KubernetesClusterUpdate) (*client.Operation[*client.KubernetesClusterExt], error) {
+	results := m.Called("UpdateWithContext", ctx, id, update)
+	if m.UpdateWithContextFunc != nil {
+		return m.UpdateWithContextFunc(ctx, id, update)
+	}
+	var r0 *client.Operation[*client.KubernetesClusterExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *KubernetesClusterService) GetKubeConfig(id string, endpoint string) (client.KubernetesClusterKubeConfigResponse, error) {
+	results := m.Called("GetKubeConfig", id, endpoint)
+	if m.GetKubeConfigFunc != nil {
+		return m.GetKubeConfigFunc(id, endpoint)
+	}
+	var r0 client.KubernetesClusterKubeConfigResponse
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *KubernetesClusterService) GetKubeConfigWithContext(ctx context.Context, id string, endpoint string) (client.KubernetesClusterKubeConfigResponse, error) {
+	results := m.Called("GetKubeConfigWithContext", ctx, id, endpoint)
+	if m.GetKubeConfigWithContextFunc != nil {
+		return m.GetKubeConfigWithContextFunc(ctx, id, endpoint)
+	}
+	var r0 client.KubernetesClusterKubeConfigResponse
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *KubernetesClusterService) GetNode(clusterId string, nodeName string) (*client.KubernetesClusterNodeInfo, error) {
+	results := m.Called("GetNode", clusterId, nodeName)
+	if m.GetNodeFunc != nil {
+		return m.GetNodeFunc(clusterId, nodeName)
+	}
+	var r0 *client.KubernetesClusterNodeInfo
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *KubernetesClusterService) GetNodeWithContext(ctx context.Context, clusterId string, nodeName string) (*client.KubernetesClusterNodeInfo, error) {
+	results := m.Called("GetNodeWithContext", ctx, clusterId, nodeName)
+	if m.GetNodeWithContextFunc != nil {
+		return m.GetNodeWithContextFunc(ctx, clusterId, nodeName)
+	}
+	var r0 *client.KubernetesClusterNodeInfo
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *KubernetesClusterService) PageNodes(clusterId string, request client.PageRequest) (*client.Page[client.KubernetesClusterNodeInfo], error) {
+	results := m.Called("PageNodes", clusterId, request)
+	if m.PageNodesFunc != nil {
+		return m.PageNodesFunc(clusterId, request)
+	}
+	var r0 *client.Page[client.KubernetesClusterNodeInfo]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *KubernetesClusterService) PageNodesWithContext(ctx context.Context, clusterId string, request client.PageRequest) (*client.Page[client.KubernetesClusterNodeInfo], error) {
+	results := m.Called("PageNodesWithContext", ctx, clusterId, request)
+	if m.PageNodesWithContextFunc != nil {
+		return m.PageNodesWithContextFunc(ctx, clusterId, request)
+	}
+	var r0 *client.Page[client.KubernetesClusterNodeInfo]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *KubernetesClusterService) AllNodes(ctx context.Context, clusterId string, request client.PageRequest) iter.Seq2[client.KubernetesClusterNodeInfo, error] {
+	results := m.Called("AllNodes", ctx, clusterId, request)
+	if m.AllNodesFunc != nil {
+		return m.AllNodesFunc(ctx, clusterId, request)
+	}
+	r0 := emptySeq2[client.KubernetesClusterNodeInfo, error]()
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *KubernetesClusterService) WaitForState(ctx context.Context, id string, state string, options client.WaitOptions) (*client.KubernetesClusterExt, error) {
+	results := m.Called("WaitForState", ctx, id, state, options)
+	if m.WaitForStateFunc != nil {
+		return m.WaitForStateFunc(ctx, id, state, options)
+	}
+	var r0 *client.KubernetesClusterExt
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+// STaaSEnvironmentService is a test double for client.STaaSEnvironmentService
+type STaaSEnvironmentService struct {
+	Mock
+	PageFunc                     func(client.PageRequest) (*client.Page[client.STaaSEnvironment], error)
+	PageWithContextFunc          func(context.Context, client.PageRequest) (*client.Page[client.STaaSEnvironment], error)
+	AllFunc                      func(context.Context, client.PageRequest) iter.Seq2[client.STaaSEnvironment, error]
+	GetFunc                      func(string) (*client.STaaSEnvironmentExt, error)
+	GetWithContextFunc           func(context.Context, string) (*client.STaaSEnvironmentExt, error)
+	CreateFunc                   func(client.STaaSEnvironmentCreate) (*client.Operation[*client.STaaSEnvironmentExt], error)
+	CreateWithContextFunc        func(context.Context, client.STaaSEnvironmentCreate) (*client.Operation[*client.STaaSEnvironmentExt], error)
+	DeleteFunc                   func(string, client.STaaSEnvironmentDelete) (*client.Operation[*client.STaaSEnvironmentExt], error)
+	DeleteWithContextFunc        func(context.Context, string, client.STaaSEnvironmentDelete) (*client.Operation[*client.STaaSEnvironmentExt], error)
+	UpdateFunc                   func(string, client.STaaSEnvironmentUpdate) (*client.Operation[*client.STaaSEnvironmentExt], error)
+	UpdateWithContextFunc        func(context.Context, string, client.STaaSEnvironmentUpdate) (*client.Operation[*client.STaaSEnvironmentExt], error)
+	CreateVolumeFunc             func(string, client.STaaSVolumeCreate) error
+	CreateVolumeWithContextFunc  func(context.Context, string, client.STaaSVolumeCreate) error
+	UpdateVolumeFunc             func(string, string, client.STaaSVolumeUpdate) error
+	UpdateVolumeWithContextFunc  func(context.Context, string, string, client.STaaSVolumeUpdate) error
+	DeleteVolumeFunc             func(string, string, client.STaaSVolumeDelete) error
+	DeleteVolumeWithContextFunc  func(context.Context, string, string, client.STaaSVolumeDelete) error
+	CreateNetworkFunc            func(string, client.STaaSNetworkCreate) error
+	CreateNetworkWithContextFunc func(context.Context, string, client.STaaSNetworkCreate) error
+	DeleteNetworkFunc            func(string, string) error
+	DeleteNetworkWithContextFunc func(context.Context, string, string) error
+	WaitForStateFunc             func(context.Context, string, string, client.WaitOptions) (*client.STaaSEnvironmentExt, error)
+}
+
+var _ client.STaaSEnvironmentService = (*STaaSEnvironmentService)(nil)
+
+func (m *STaaSEnvironmentService) Page(request client.PageRequest) (*client.Page[client.STaaSEnvironment], error) {
+	results := m.Called("Page", request)
+	if m.PageFunc != nil {
+		return m.PageFunc(request)
+	}
+	var r0 *client.Page[client.STaaSEnvironment]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *STaaSEnvironmentService) PageWithContext(ctx context.Context, request client.PageRequest) (*client.Page[client.STaaSEnvironment], error) {
+	results := m.Called("PageWithContext", ctx, request)
+	if m.PageWithContextFunc != nil {
+		return m.PageWithContextFunc(ctx, request)
+	}
+	var r0 *client.Page[client.STaaSEnvironment]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *STaaSEnvironmentService) All(ctx context.Context, request client.PageRequest) iter.Seq2[client.STaaSEnvironment, error] {
+	results := m.Called("All", ctx, request)
+	if m.AllFunc != nil {
+		return m.AllFunc(ctx, request)
+	}
+	r0 := emptySeq2[client.STaaSEnvironment, error]()
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *STaaSEnvironmentService) Get(id string) (*client.STaaSEnvironmentExt, error) {
+	results := m.Called("Get", id)
+	if m.GetFunc != nil {
+		return m.GetFunc(id)
+	}
+	var r0 *client.STaaSEnvironmentExt
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *STaaSEnvironmentService) GetWithContext(ctx context.Context, id string) (*client.STaaSEnvironmentExt, error) {
+	results := m.Called("GetWithContext", ctx, id)
+	if m.GetWithContextFunc != nil {
+		return m.GetWithContextFunc(ctx, id)
+	}
+	var r0 *client.STaaSEnvironmentExt
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *STaaSEnvironmentService) Create(create client.STaaSEnvironmentCreate) (*client.Operation[*client.STaaSEnvironmentExt], error) {
+	results := m.Called("Create", create)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(create)
+	}
+	var r0 *client.Operation[*client.STaaSEnvironmentExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *STaaSEnvironmentService) CreateWithContext(ctx context.Context, create client.STaaSEnvironmentCreate) (*client.Operation[*client.STaaSEnvironmentExt], error) {
+	results := m.Called("CreateWithContext", ctx, create)
+	if m.CreateWithContextFunc != nil {
+		return m.CreateWithContextFunc(ctx, create)
+	}
+	var r0 *client.Operation[*client.STaaSEnvironmentExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *STaaSEnvironmentService) Delete(id string, delete client.STaaSEnvironmentDelete) (*client.Operation[*client.STaaSEnvironmentExt], error) {
+	results := m.Called("Delete", id, delete)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id, delete)
+	}
+	var r0 *client.Operation[*client.STaaSEnvironmentExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *STaaSEnvironmentService) DeleteWithContext(ctx context.Context, id string, delete client.STaaSEnvironmentDelete) (*client.Operation[*client.STaaSEnvironmentExt], error) {
+	results := m.Called("DeleteWithContext", ctx, id, delete)
+	if m.DeleteWithContextFunc != nil {
+		return m.DeleteWithContextFunc(ctx, id, delete)
+	}
+	var r0 *client.Operation[*client.STaaSEnvironmentExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *STaaSEnvironmentService) Update(id string, update client.STaaSEnvironmentUpdate) (*client.Operation[*client.STaaSEnvironmentExt], error) {
+	results := m.Called("Update", id, update)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(id, update)
+	}
+	var r0 *client.Operation[*client.STaaSEnvironmentExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *STaaSEnvironmentService) UpdateWithContext(ctx context.Context, id string, update client.STaaSEnvironmentUpdate) (*client.Operation[*client.STaaSEnvironmentExt], error) {
+	results := m.Called("UpdateWithContext", ctx, id, update)
+	if m.UpdateWithContextFunc != nil {
+		return m.UpdateWithContextFunc(ctx, id, update)
+	}
+	var r0 *client.Operation[*client.STaaSEnvironmentExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *STaaSEnvironmentService) CreateVolume(id string, create client.STaaSVolumeCreate) error {
+	results := m.Called("CreateVolume", id, create)
+	if m.CreateVolumeFunc != nil {
+		return m.CreateVolumeFunc(id, create)
+	}
+	var r0 error
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *STaaSEnvironmentService) CreateVolumeWithContext(ctx context.Context, id string, create client.STaaSVolumeCreate) error {
+	results := m.Called("CreateVolumeWithContext", ctx, id, create)
+	if m.CreateVolumeWithContextFunc != nil {
+		return m.CreateVolumeWithContextFunc(ctx, id, create)
+	}
+	var r0 error
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *STaaSEnvironmentService) UpdateVolume(id string, volumeId string, update client.STaaSVolumeUpdate) error {
+	results := m.Called("UpdateVolume", id, volumeId, update)
+	if m.UpdateVolumeFunc != nil {
+		return m.UpdateVolumeFunc(id, volumeId, update)
+	}
+	var r0 error
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *STaaSEnvironmentService) UpdateVolumeWithContext(ctx context.Context, id string, volumeId string, update client.STaaSVolumeUpdate) error {
+	results := m.Called("UpdateVolumeWithContext", ctx, id, volumeId, update)
+	if m.UpdateVolumeWithContextFunc != nil {
+		return m.UpdateVolumeWithContextFunc(ctx, id, volumeId, update)
+	}
+	var r0 error
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *STaaSEnvironmentService) DeleteVolume(id string, volumeId string, delete client.STaaSVolumeDelete) error {
+	results := m.Called("DeleteVolume", id, volumeId, delete)
+	if m.DeleteVolumeFunc != nil {
+		return m.DeleteVolumeFunc(id, volumeId, delete)
+	}
+	var r0 error
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *STaaSEnvironmentService) DeleteVolumeWithContext(ctx context.Context, id string, volumeId string, delete client.STaaSVolumeDelete) error {
+	results := m.Called("DeleteVolumeWithContext", ctx, id, volumeId, delete)
+	if m.DeleteVolumeWithContextFunc != nil {
+		return m.DeleteVolumeWithContextFunc(ctx, id, volumeId, delete)
+	}
+	var r0 error
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *STaaSEnvironmentService) CreateNetwork(id string, create client.STaaSNetworkCreate) error {
+	results := m.Called("CreateNetwork", id, create)
+	if m.CreateNetworkFunc != nil {
+		return m.CreateNetworkFunc(id, create)
+	}
+	var r0 error
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *STaaSEnvironmentService) CreateNetworkWithContext(ctx context.Context, id string, create client.STaaSNetworkCreate) error {
+	results := m.Called("CreateNetworkWithContext", ctx, id, create)
+	if m.CreateNetworkWithContextFunc != nil {
+		return m.CreateNetworkWithContextFunc(ctx, id, create)
+	}
+	var r0 error
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *STaaSEnvironmentService) DeleteNetwork(id string, networkId string) error {
+	results := m.Called("DeleteNetwork", id, networkId)
+	if m.DeleteNetworkFunc != nil {
+		return m.DeleteNetworkFunc(id, networkId)
+	}
+	var r0 error
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *STaaSEnvironmentService) DeleteNetworkWithContext(ctx context.Context, id string, networkId string) error {
+	results := m.Called("DeleteNetworkWithContext", ctx, id, networkId)
+	if m.DeleteNetworkWithContextFunc != nil {
+		return m.DeleteNetworkWithContextFunc(ctx, id, networkId)
+	}
+	var r0 error
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *STaaSEnvironmentService) WaitForState(ctx context.Context, id string, state string, options client.WaitOptions) (*client.STaaSEnvironmentExt, error) {
+	results := m.Called("WaitForState", ctx, id, state, options)
+	if m.WaitForStateFunc != nil {
+		return m.WaitForStateFunc(ctx, id, state, options)
+	}
+	var r0 *client.STaaSEnvironmentExt
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+// TaskService is a test double for client.TaskService
+type TaskService struct {
+	Mock
+	ListFunc                   func() (*[]client.Task, error)
+	ListWithContextFunc        func(context.Context) (*[]client.Task, error)
+	HistoryFunc                func(client.TaskFilter, int, int) (*client.Page[client.Task], error)
+	HistoryWithContextFunc     func(context.Context, client.TaskFilter, int, int) (*client.Page[client.Task], error)
+	GetFunc                    func(string) (*client.Task, error)
+	GetWithContextFunc         func(context.Context, string) (*client.Task, error)
+	WaitForFunc                func(string, time.Duration) (*client.Task, error)
+	WaitForWithContextFunc     func(context.Context, string, time.Duration) (*client.Task, error)
+	WaitForTaskFunc            func(*client.Task, time.Duration) (*client.Task, error)
+	WaitForTaskWithContextFunc func(context.Context, *client.Task, time.Duration) (*client.Task, error)
+	WaitForWithOptionsFunc     func(context.Context, string, client.WaitOptions) (*client.Task, error)
+	WaitForWithProgressFunc    func(context.Context, string, client.WaitOptions, func(client.TaskProgress)) (*client.Task, error)
+	WatchFunc                  func(context.Context, string, client.WaitOptions) <-chan client.TaskProgress
+	WaitForEachFunc            func(context.Context, []string, client.WaitOptions) iter.Seq[client.TaskResult]
+	WaitForAllFunc             func(context.Context, []string, client.WaitOptions) ([]client.TaskResult, error)
+	WaitForAnyFunc             func(context.Context, []string, client.WaitOptions) (client.TaskResult, error)
+}
+
+var _ client.TaskService = (*TaskService)(nil)
+
+func (m *TaskService) List() (*[]client.Task, error) {
+	results := m.Called("List")
+	if m.ListFunc != nil {
+		return m.ListFunc()
+	}
+	var r0 *[]client.Task
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *TaskService) ListWithContext(ctx context.Context) (*[]client.Task, error) {
+	results := m.Called("ListWithContext", ctx)
+	if m.ListWithContextFunc != nil {
+		return m.ListWithContextFunc(ctx)
+	}
+	var r0 *[]client.Task
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *TaskService) History(filter client.TaskFilter, page int, size int) (*client.Page[client.Task], error) {
+	results := m.Called("History", filter, page, size)
+	if m.HistoryFunc != nil {
+		return m.HistoryFunc(filter, page, size)
+	}
+	var r0 *client.Page[client.Task]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *TaskService) HistoryWithContext(ctx context.Context, filter client.TaskFilter, page int, size int) (*client.Page[client.Task], error) {
+	results := m.Called("HistoryWithContext", ctx, filter, page, size)
+	if m.HistoryWithContextFunc != nil {
+		return m.HistoryWithContextFunc(ctx, filter, page, size)
+	}
+	var r0 *client.Page[client.Task]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *TaskService) Get(id string) (*client.Task, error) {
+	results := m.Called("Get", id)
+	if m.GetFunc != nil {
+		return m.GetFunc(id)
+	}
+	var r0 *client.Task
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *TaskService) GetWithContext(ctx context.Context, id string) (*client.Task, error) {
+	results := m.Called("GetWithContext", ctx, id)
+	if m.GetWithContextFunc != nil {
+		return m.GetWithContextFunc(ctx, id)
+	}
+	var r0 *client.Task
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *TaskService) WaitFor(id string, timeoutDuration time.Duration) (*client.Task, error) {
+	results := m.Called("WaitFor", id, timeoutDuration)
+	if m.WaitForFunc != nil {
+		return m.WaitForFunc(id, timeoutDuration)
+	}
+	var r0 *client.Task
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *TaskService) WaitForWithContext(ctx context.Context, id string, timeoutDuration time.Duration) (*client.Task, error) {
+	results := m.Called("WaitForWithContext", ctx, id, timeoutDuration)
+	if m.WaitForWithContextFunc != nil {
+		return m.WaitForWithContextFunc(ctx, id, timeoutDuration)
+	}
+	var r0 *client.Task
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *TaskService) WaitForTask(task *client.Task, timeoutDuration time.Duration) (*client.Task, error) {
+	results := m.Called("WaitForTask", task, timeoutDuration)
+	if m.WaitForTaskFunc != nil {
+		return m.WaitForTaskFunc(task, timeoutDuration)
+	}
+	var r0 *client.Task
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *TaskService) WaitForTaskWithContext(ctx context.Context, task *client.Task, timeoutDuration time.Duration) (*client.Task, error) {
+	results := m.Called("WaitForTaskWithContext", ctx, task, timeoutDuration)
+	if m.WaitForTaskWithContextFunc != nil {
+		return m.WaitForTaskWithContextFunc(ctx, task, timeoutDuration)
+	}
+	var r0 *client.Task
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *TaskService) WaitForWithOptions(ctx context.Context, id string, options client.WaitOptions) (*client.Task, error) {
+	results := m.Called("WaitForWithOptions", ctx, id, options)
+	if m.WaitForWithOptionsFunc != nil {
+		return m.WaitForWithOptionsFunc(ctx, id, options)
+	}
+	var r0 *client.Task
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *TaskService) WaitForWithProgress(ctx context.Context, id string, options client.WaitOptions, onProgress func(client.TaskProgress)) (*client.Task, error) {
+	results := m.Called("WaitForWithProgress", ctx, id, options, onProgress)
+	if m.WaitForWithProgressFunc != nil {
+		return m.WaitForWithProgressFunc(ctx, id, options, onProgress)
+	}
+	var r0 *client.Task
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *TaskService) Watch(ctx context.Context, id string, options client.WaitOptions) <-chan client.TaskProgress {
+	results := m.Called("Watch", ctx, id, options)
+	if m.WatchFunc != nil {
+		return m.WatchFunc(ctx, id, options)
+	}
+	var r0 <-chan client.TaskProgress
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *TaskService) WaitForEach(ctx context.Context, ids []string, options client.WaitOptions) iter.Seq[client.TaskResult] {
+	results := m.Called("WaitForEach", ctx, ids, options)
+	if m.WaitForEachFunc != nil {
+		return m.WaitForEachFunc(ctx, ids, options)
+	}
+	r0 := emptySeq[client.TaskResult]()
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *TaskService) WaitForAll(ctx context.Context, ids []string, options client.WaitOptions) ([]client.TaskResult, error) {
+	results := m.Called("WaitForAll", ctx, ids, options)
+	if m.WaitForAllFunc != nil {
+		return m.WaitForAllFunc(ctx, ids, options)
+	}
+	var r0 []client.TaskResult
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *TaskService) WaitForAny(ctx context.Context, ids []string, options client.WaitOptions) (client.TaskResult, error) {
+	results := m.Called("WaitForAny", ctx, ids, options)
+	if m.WaitForAnyFunc != nil {
+		return m.WaitForAnyFunc(ctx, ids, options)
+	}
+	var r0 client.TaskResult
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+// VirtualFirewallService is a test double for client.VirtualFirewallService
+type VirtualFirewallService struct {
+	Mock
+	PageFunc                     func(client.PageRequest) (*client.Page[client.VirtualFirewall], error)
+	PageWithContextFunc          func(context.Context, client.PageRequest) (*client.Page[client.VirtualFirewall], error)
+	AllFunc                      func(context.Context, client.PageRequest) iter.Seq2[client.VirtualFirewall, error]
+	GetFunc                      func(string) (*client.VirtualFirewallExt, error)
+	GetWithContextFunc           func(context.Context, string) (*client.VirtualFirewallExt, error)
+	CreateFunc                   func(client.VirtualFirewallCreate) (*client.Operation[*client.VirtualFirewallExt], error)
+	CreateWithContextFunc        func(context.Context, client.VirtualFirewallCreate) (*client.Operation[*client.VirtualFirewallExt], error)
+	DeleteFunc                   func(string) (*client.Operation[*client.VirtualFirewallExt], error)
+	DeleteWithContextFunc        func(context.Context, string) (*client.Operation[*client.VirtualFirewallExt], error)
+	UpdateFunc                   func(string, client.VirtualFirewallUpdate) (*client.Operation[*client.VirtualFirewallExt], error)
+	UpdateWithContextFunc        func(context.Context, string, client.VirtualFirewallUpdate) (*client.Operation[*client.VirtualFirewallExt], error)
+	PageNatRulesFunc             func(string, client.PageRequest) (*client.Page[client.VirtualFirewallNatRule], error)
+	PageNatRulesWithContextFunc  func(context.Context, string, client.PageRequest) (*client.Page[client.VirtualFirewallNatRule], error)
+	AllNatRulesFunc              func(context.Context, string, client.PageRequest) iter.Seq2[client.VirtualFirewallNatRule, error]
+	CreateNatRuleFunc            func(string, client.VirtualFirewallNatRuleCreate) (*client.Reference, error)
+	CreateNatRuleWithContextFunc func(context.Context, string, client.VirtualFirewallNatRuleCreate) (*client.Reference, error)
+	UpdateNatRuleFunc            func(string, string, client.VirtualFirewallNatRuleCreate) error
+	UpdateNatRuleWithContextFunc func(context.Context, string, string, client.VirtualFirewallNatRuleCreate) error
+	DeleteNatRuleFunc            func(string, string) error
+	DeleteNatRuleWithContextFunc func(context.Context, string, string) error
+	WaitForStateFunc             func(context.Context, string, string, client.WaitOptions) (*client.VirtualFirewallExt, error)
+}
+
+var _ client.VirtualFirewallService = (*VirtualFirewallService)(nil)
+
+func (m *VirtualFirewallService) Page(request client.PageRequest) (*client.Page[client.VirtualFirewall], error) {
+	results := m.Called("Page", request)
+	if m.PageFunc != nil {
+		return m.PageFunc(request)
+	}
+	var r0 *client.Page[client.VirtualFirewall]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualFirewallService) PageWithContext(ctx context.Context, request client.PageRequest) (*client.Page[client.VirtualFirewall], error) {
+	results := m.Called("PageWithContext", ctx, request)
+	if m.PageWithContextFunc != nil {
+		return m.PageWithContextFunc(ctx, request)
+	}
+	var r0 *client.Page[client.VirtualFirewall]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualFirewallService) All(ctx context.Context, request client.PageRequest) iter.Seq2[client.VirtualFirewall, error] {
+	results := m.Called("All", ctx, request)
+	if m.AllFunc != nil {
+		return m.AllFunc(ctx, request)
+	}
+	r0 := emptySeq2[client.VirtualFirewall, error]()
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *VirtualFirewallService) Get(id string) (*client.VirtualFirewallExt, error) {
+	results := m.Called("Get", id)
+	if m.GetFunc != nil {
+		return m.GetFunc(id)
+	}
+	var r0 *client.VirtualFirewallExt
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualFirewallService) GetWithContext(ctx context.Context, id string) (*client.VirtualFirewallExt, error) {
+	results := m.Called("GetWithContext", ctx, id)
+	if m.GetWithContextFunc != nil {
+		return m.GetWithContextFunc(ctx, id)
+	}
+	var r0 *client.VirtualFirewallExt
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualFirewallService) Create(create client.VirtualFirewallCreate) (*client.Operation[*client.VirtualFirewallExt], error) {
+	results := m.Called("Create", create)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(create)
+	}
+	var r0 *client.Operation[*client.VirtualFirewallExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualFirewallService) CreateWithContext(ctx context.Context, create client.VirtualFirewallCreate) (*client.Operation[*client.VirtualFirewallExt], error) {
+	results := m.Called("CreateWithContext", ctx, create)
+	if m.CreateWithContextFunc != nil {
+		return m.CreateWithContextFunc(ctx, create)
+	}
+	var r0 *client.Operation[*client.VirtualFirewallExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualFirewallService) Delete(id string) (*client.Operation[*client.VirtualFirewallExt], error) {
+	results := m.Called("Delete", id)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id)
+	}
+	var r0 *client.Operation[*client.VirtualFirewallExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualFirewallService) DeleteWithContext(ctx context.Context, id string) (*client.Operation[*client.VirtualFirewallExt], error) {
+	results := m.Called("DeleteWithContext", ctx, id)
+	if m.DeleteWithContextFunc != nil {
+		return m.DeleteWithContextFunc(ctx, id)
+	}
+	var r0 *client.Operation[*client.VirtualFirewallExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualFirewallService) Update(id string, update client.VirtualFirewallUpdate) (*client.Operation[*client.VirtualFirewallExt], error) {
+	results := m.Called("Update", id, update)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(id, update)
+	}
+	var r0 *client.Operation[*client.VirtualFirewallExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualFirewallService) UpdateWithContext(ctx context.Context, id string, update client.VirtualFirewallUpdate) (*client.Operation[*client.VirtualFirewallExt], error) {
+	results := m.Called("UpdateWithContext", ctx, id, update)
+	if m.UpdateWithContextFunc != nil {
+		return m.UpdateWithContextFunc(ctx, id, update)
+	}
+	var r0 *client.Operation[*client.VirtualFirewallExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualFirewallService) PageNatRules(firewallId string, request client.PageRequest) (*client.Page[client.VirtualFirewallNatRule], error) {
+	results := m.Called("PageNatRules", firewallId, request)
+	if m.PageNatRulesFunc != nil {
+		return m.PageNatRulesFunc(firewallId, request)
+	}
+	var r0 *client.Page[client.VirtualFirewallNatRule]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualFirewallService) PageNatRulesWithContext(ctx context.Context, firewallId string, request client.PageRequest) (*client.Page[client.VirtualFirewallNatRule], error) {
+	results := m.Called("PageNatRulesWithContext", ctx, firewallId, request)
+	if m.PageNatRulesWithContextFunc != nil {
+		return m.PageNatRulesWithContextFunc(ctx, firewallId, request)
+	}
+	var r0 *client.Page[client.VirtualFirewallNatRule]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualFirewallService) AllNatRules(ctx context.Context, firewallId string, request client.PageRequest) iter.Seq2[client.VirtualFirewallNatRule, error] {
+	results := m.Called("AllNatRules", ctx, firewallId, request)
+	if m.AllNatRulesFunc != nil {
+		return m.AllNatRulesFunc(ctx, firewallId, request)
+	}
+	r0 := emptySeq2[client.VirtualFirewallNatRule, error]()
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *VirtualFirewallService) CreateNatRule(firewallId string, create client.VirtualFirewallNatRuleCreate) (*client.Reference, error) {
+	results := m.Called("CreateNatRule", firewallId, create)
+	if m.CreateNatRuleFunc != nil {
+		return m.CreateNatRuleFunc(firewallId, create)
+	}
+	var r0 *client.Reference
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualFirewallService) CreateNatRuleWithContext(ctx context.Context, firewallId string, create client.VirtualFirewallNatRuleCreate) (*client.Reference, error) {
+	results := m.Called("CreateNatRuleWithContext", ctx, firewallId, create)
+	if m.CreateNatRuleWithContextFunc != nil {
+		return m.CreateNatRuleWithContextFunc(ctx, firewallId, create)
+	}
+	var r0 *client.Reference
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualFirewallService) UpdateNatRule(firewallId string, id string, create client.VirtualFirewallNatRuleCreate) error {
+	results := m.Called("UpdateNatRule", firewallId, id, create)
+	if m.UpdateNatRuleFunc != nil {
+		return m.UpdateNatRuleFunc(firewallId, id, create)
+	}
+	var r0 error
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *VirtualFirewallService) UpdateNatRuleWithContext(ctx context.Context, firewallId string, id string, create client.VirtualFirewallNatRuleCreate) error {
+	results := m.Called("UpdateNatRuleWithContext", ctx, firewallId, id, create)
+	if m.UpdateNatRuleWithContextFunc != nil {
+		return m.UpdateNatRuleWithContextFunc(ctx, firewallId, id, create)
+	}
+	var r0 error
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *VirtualFirewallService) DeleteNatRule(firewallId string, id string) error {
+	results := m.Called("DeleteNatRule", firewallId, id)
+	if m.DeleteNatRuleFunc != nil {
+		return m.DeleteNatRuleFunc(firewallId, id)
+	}
+	var r0 error
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *VirtualFirewallService) DeleteNatRuleWithContext(ctx context.Context, firewallId string, id string) error {
+	results := m.Called("DeleteNatRuleWithContext", ctx, firewallId, id)
+	if m.DeleteNatRuleWithContextFunc != nil {
+		return m.DeleteNatRuleWithContextFunc(ctx, firewallId, id)
+	}
+	var r0 error
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *VirtualFirewallService) WaitForState(ctx context.Context, id string, state string, options client.WaitOptions) (*client.VirtualFirewallExt, error) {
+	results := m.Called("WaitForState", ctx, id, state, options)
+	if m.WaitForStateFunc != nil {
+		return m.WaitForStateFunc(ctx, id, state, options)
+	}
+	var r0 *client.VirtualFirewallExt
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+// VirtualNetworkService is a test double for client.VirtualNetworkService
+type VirtualNetworkService struct {
+	Mock
+	PageFunc              func(client.PageRequest) (*client.Page[client.VirtualNetwork], error)
+	PageWithContextFunc   func(context.Context, client.PageRequest) (*client.Page[client.VirtualNetwork], error)
+	AllFunc               func(context.Context, client.PageRequest) iter.Seq2[client.VirtualNetwork, error]
+	GetFunc               func(string) (*client.VirtualNetwork, error)
+	GetWithContextFunc    func(context.Context, string) (*client.VirtualNetwork, error)
+	CreateFunc            func(*client.VirtualNetworkUpdate) (*client.Operation[*client.VirtualNetwork], error)
+	CreateWithContextFunc func(context.Context, *client.VirtualNetworkUpdate) (*client.Operation[*client.VirtualNetwork], error)
+	DeleteFunc            func(string) (*client.Operation[*client.VirtualNetwork], error)
+	DeleteWithContextFunc func(context.Context, string) (*client.Operation[*client.VirtualNetwork], error)
+	UpdateFunc            func(string, *client.VirtualNetworkUpdate) (*client.Operation[*client.VirtualNetwork], error)
+	UpdateWithContextFunc func(context.Context, string, *client.VirtualNetworkUpdate) (*client.Operation[*client.VirtualNetwork], error)
+	WaitForStateFunc      func(context.Context, string, string, client.WaitOptions) (*client.VirtualNetwork, error)
+	WaitUntilReadyFunc    func(context.Context, string, client.WaitOptions) (*client.VirtualNetwork, error)
+}
+
+var _ client.VirtualNetworkService = (*VirtualNetworkService)(nil)
+
+func (m *VirtualNetworkService) Page(request client.PageRequest) (*client.Page[client.VirtualNetwork], error) {
+	results := m.Called("Page", request)
+	if m.PageFunc != nil {
+		return m.PageFunc(request)
+	}
+	var r0 *client.Page[client.VirtualNetwork]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualNetworkService) PageWithContext(ctx context.Context, request client.PageRequest) (*client.Page[client.VirtualNetwork], error) {
+	results := m.Called("PageWithContext", ctx, request)
+	if m.PageWithContextFunc != nil {
+		return m.PageWithContextFunc(ctx, request)
+	}
+	var r0 *client.Page[client.VirtualNetwork]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualNetworkService) All(ctx context.Context, request client.PageRequest) iter.Seq2[client.VirtualNetwork, error] {
+	results := m.Called("All", ctx, request)
+	if m.AllFunc != nil {
+		return m.AllFunc(ctx, request)
+	}
+	r0 := emptySeq2[client.VirtualNetwork, error]()
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *VirtualNetworkService) Get(id string) (*client.VirtualNetwork, error) {
+	results := m.Called("Get", id)
+	if m.GetFunc != nil {
+		return m.GetFunc(id)
+	}
+	var r0 *client.VirtualNetwork
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualNetworkService) GetWithContext(ctx context.Context, id string) (*client.VirtualNetwork, error) {
+	results := m.Called("GetWithContext", ctx, id)
+	if m.GetWithContextFunc != nil {
+		return m.GetWithContextFunc(ctx, id)
+	}
+	var r0 *client.VirtualNetwork
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualNetworkService) Create(vn *client.VirtualNetworkUpdate) (*client.Operation[*client.VirtualNetwork], error) {
+	results := m.Called("Create", vn)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(vn)
+	}
+	var r0 *client.Operation[*client.VirtualNetwork]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualNetworkService) CreateWithContext(ctx context.Context, vn *client.VirtualNetworkUpdate) (*client.Operation[*client.VirtualNetwork], error) {
+	results := m.Called("CreateWithContext", ctx, vn)
+	if m.CreateWithContextFunc != nil {
+		return m.CreateWithContextFunc(ctx, vn)
+	}
+	var r0 *client.Operation[*client.VirtualNetwork]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualNetworkService) Delete(id string) (*client.Operation[*client.VirtualNetwork], error) {
+	results := m.Called("Delete", id)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id)
+	}
+	var r0 *client.Operation[*client.VirtualNetwork]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualNetworkService) DeleteWithContext(ctx context.Context, id string) (*client.Operation[*client.VirtualNetwork], error) {
+	results := m.Called("DeleteWithContext", ctx, id)
+	if m.DeleteWithContextFunc != nil {
+		return m.DeleteWithContextFunc(ctx, id)
+	}
+	var r0 *client.Operation[*client.VirtualNetwork]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualNetworkService) Update(id string, vn *client.VirtualNetworkUpdate) (*client.Operation[*client.VirtualNetwork], error) {
+	results := m.Called("Update", id, vn)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(id, vn)
+	}
+	var r0 *client.Operation[*client.VirtualNetwork]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualNetworkService) UpdateWithContext(ctx context.Context, id string, vn *client.VirtualNetworkUpdate) (*client.Operation[*client.VirtualNetwork], error) {
+	results := m.Called("UpdateWithContext", ctx, id, vn)
+	if m.UpdateWithContextFunc != nil {
+		return m.UpdateWithContextFunc(ctx, id, vn)
+	}
+	var r0 *client.Operation[*client.VirtualNetwork]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualNetworkService) WaitForState(ctx context.Context, id string, state string, options client.WaitOptions) (*client.VirtualNetwork, error) {
+	results := m.Called("WaitForState", ctx, id, state, options)
+	if m.WaitForStateFunc != nil {
+		return m.WaitForStateFunc(ctx, id, state, options)
+	}
+	var r0 *client.VirtualNetwork
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualNetworkService) WaitUntilReady(ctx context.Context, id string, options client.WaitOptions) (*client.VirtualNetwork, error) {
+	results := m.Called("WaitUntilReady", ctx, id, options)
+	if m.WaitUntilReadyFunc != nil {
+		return m.WaitUntilReadyFunc(ctx, id, options)
+	}
+	var r0 *client.VirtualNetwork
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+// VirtualServerService is a test double for client.VirtualServerService
+type VirtualServerService struct {
+	Mock
+	ComputeClusterListFunc                    func() (*[]client.ComputeCluster, error)
+	ComputeClusterListWithContextFunc         func(context.Context) (*[]client.ComputeCluster, error)
+	VirtualMachineTemplateListFunc            func() (*[]client.VirtualMachineTemplate, error)
+	VirtualMachineTemplateListWithContextFunc func(context.Context) (*[]client.VirtualMachineTemplate, error)
+	PageFunc                                  func(client.PageRequest) (*client.Page[client.VirtualMachine], error)
+	PageWithContextFunc                       func(context.Context, client.PageRequest) (*client.Page[client.VirtualMachine], error)
+	AllFunc                                   func(context.Context, client.PageRequest) iter.Seq2[client.VirtualMachine, error]
+	GetFunc                                   func(string) (*client.VirtualMachineExt, error)
+	GetWithContextFunc                        func(context.Context, string) (*client.VirtualMachineExt, error)
+	CreateFunc                                func(*client.VirtualMachineCreate) (*client.Operation[*client.VirtualMachineExt], error)
+	CreateWithContextFunc                     func(context.Context, *client.VirtualMachineCreate) (*client.Operation[*client.VirtualMachineExt], error)
+	DeleteFunc                                func(string) (*client.Operation[*client.VirtualMachineExt], error)
+	DeleteWithContextFunc                     func(context.Context, string) (*client.Operation[*client.VirtualMachineExt], error)
+	UpdateFunc                                func(string, *client.VirtualMachineUpdate) (*client.Operation[*client.VirtualMachineExt], error)
+	UpdateWithContextFunc                     func(context.Context, string, *client.VirtualMachineUpdate) (*client.Operation[*client.VirtualMachineExt], error)
+	ControlFunc                               func(string, client.VmAction) (*client.VirtualMachineTask, error)
+	ControlWithContextFunc                    func(context.Context, string, client.VmAction) (*client.VirtualMachineTask, error)
+	PowerOnFunc                               func(string, *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error)
+	PowerOnWithContextFunc                    func(context.Context, string, *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error)
+	PowerOffFunc                              func(string, *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error)
+	PowerOffWithContextFunc                   func(context.Context, string, *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error)
+	ShutdownFunc                              func(string, *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error)
+	ShutdownWithContextFunc                   func(context.Context, string, *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error)
+	RebootFunc                                func(string, *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error)
+	RebootWithContextFunc                     func(context.Context, string, *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error)
+	SuspendFunc                               func(string, *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error)
+	SuspendWithContextFunc                    func(context.Context, string, *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error)
+	ResetFunc                                 func(string, *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error)
+	ResetWithContextFunc                      func(context.Context, string, *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error)
+	OpenConsoleFunc                           func(string) (*client.OpenConsoleResult, error)
+	OpenConsoleWithContextFunc                func(context.Context, string) (*client.OpenConsoleResult, error)
+}
+
+var _ client.VirtualServerService = (*VirtualServerService)(nil)
+
+func (m *VirtualServerService) ComputeClusterList() (*[]client.ComputeCluster, error) {
+	results := m.Called("ComputeClusterList")
+	if m.ComputeClusterListFunc != nil {
+		return m.ComputeClusterListFunc()
+	}
+	var r0 *[]client.ComputeCluster
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) ComputeClusterListWithContext(ctx context.Context) (*[]client.ComputeCluster, error) {
+	results := m.Called("ComputeClusterListWithContext", ctx)
+	if m.ComputeClusterListWithContextFunc != nil {
+		return m.ComputeClusterListWithContextFunc(ctx)
+	}
+	var r0 *[]client.ComputeCluster
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) VirtualMachineTemplateList() (*[]client.VirtualMachineTemplate, error) {
+	results := m.Called("VirtualMachineTemplateList")
+	if m.VirtualMachineTemplateListFunc != nil {
+		return m.VirtualMachineTemplateListFunc()
+	}
+	var r0 *[]client.VirtualMachineTemplate
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) VirtualMachineTemplateListWithContext(ctx context.Context) (*[]client.VirtualMachineTemplate, error) {
+	results := m.Called("VirtualMachineTemplateListWithContext", ctx)
+	if m.VirtualMachineTemplateListWithContextFunc != nil {
+		return m.VirtualMachineTemplateListWithContextFunc(ctx)
+	}
+	var r0 *[]client.VirtualMachineTemplate
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) Page(request client.PageRequest) (*client.Page[client.VirtualMachine], error) {
+	results := m.Called("Page", request)
+	if m.PageFunc != nil {
+		return m.PageFunc(request)
+	}
+	var r0 *client.Page[client.VirtualMachine]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) PageWithContext(ctx context.Context, request client.PageRequest) (*client.Page[client.VirtualMachine], error) {
+	results := m.Called("PageWithContext", ctx, request)
+	if m.PageWithContextFunc != nil {
+		return m.PageWithContextFunc(ctx, request)
+	}
+	var r0 *client.Page[client.VirtualMachine]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) All(ctx context.Context, request client.PageRequest) iter.Seq2[client.VirtualMachine, error] {
+	results := m.Called("All", ctx, request)
+	if m.AllFunc != nil {
+		return m.AllFunc(ctx, request)
+	}
+	r0 := emptySeq2[client.VirtualMachine, error]()
+	result(results, 0, &r0)
+	return r0
+}
+
+func (m *VirtualServerService) Get(id string) (*client.VirtualMachineExt, error) {
+	results := m.Called("Get", id)
+	if m.GetFunc != nil {
+		return m.GetFunc(id)
+	}
+	var r0 *client.VirtualMachineExt
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) GetWithContext(ctx context.Context, id string) (*client.VirtualMachineExt, error) {
+	results := m.Called("GetWithContext", ctx, id)
+	if m.GetWithContextFunc != nil {
+		return m.GetWithContextFunc(ctx, id)
+	}
+	var r0 *client.VirtualMachineExt
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) Create(vm *client.VirtualMachineCreate) (*client.Operation[*client.VirtualMachineExt], error) {
+	results := m.Called("Create", vm)
+	if m.CreateFunc != nil {
+		return m.CreateFunc(vm)
+	}
+	var r0 *client.Operation[*client.VirtualMachineExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) CreateWithContext(ctx context.Context, vm *client.VirtualMachineCreate) (*client.Operation[*client.VirtualMachineExt], error) {
+	results := m.Called("CreateWithContext", ctx, vm)
+	if m.CreateWithContextFunc != nil {
+		return m.CreateWithContextFunc(ctx, vm)
+	}
+	var r0 *client.Operation[*client.VirtualMachineExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) Delete(id string) (*client.Operation[*client.VirtualMachineExt], error) {
+	results := m.Called("Delete", id)
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(id)
+	}
+	var r0 *client.Operation[*client.VirtualMachineExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) DeleteWithContext(ctx context.Context, id string) (*client.Operation[*client.VirtualMachineExt], error) {
+	results := m.Called("DeleteWithContext", ctx, id)
+	if m.DeleteWithContextFunc != nil {
+		return m.DeleteWithContextFunc(ctx, id)
+	}
+	var r0 *client.Operation[*client.VirtualMachineExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) Update(id string, vm *client.VirtualMachineUpdate) (*client.Operation[*client.VirtualMachineExt], error) {
+	results := m.Called("Update", id, vm)
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(id, vm)
+	}
+	var r0 *client.Operation[*client.VirtualMachineExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) UpdateWithContext(ctx context.Context, id string, vm *client.VirtualMachineUpdate) (*client.Operation[*client.VirtualMachineExt], error) {
+	results := m.Called("UpdateWithContext", ctx, id, vm)
+	if m.UpdateWithContextFunc != nil {
+		return m.UpdateWithContextFunc(ctx, id, vm)
+	}
+	var r0 *client.Operation[*client.VirtualMachineExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) Control(id string, action client.VmAction) (*client.VirtualMachineTask, error) {
+	results := m.Called("Control", id, action)
+	if m.ControlFunc != nil {
+		return m.ControlFunc(id, action)
+	}
+	var r0 *client.VirtualMachineTask
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) ControlWithContext(ctx context.Context, id string, action client.VmAction) (*client.VirtualMachineTask, error) {
+	results := m.Called("ControlWithContext", ctx, id, action)
+	if m.ControlWithContextFunc != nil {
+		return m.ControlWithContextFunc(ctx, id, action)
+	}
+	var r0 *client.VirtualMachineTask
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) PowerOn(id string, wait *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error) {
+	results := m.Called("PowerOn", id, wait)
+	if m.PowerOnFunc != nil {
+		return m.PowerOnFunc(id, wait)
+	}
+	var r0 *client.Operation[*client.VirtualMachineExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) PowerOnWithContext(ctx context.Context, id string, wait *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error) {
+	results := m.Called("PowerOnWithContext", ctx, id, wait)
+	if m.PowerOnWithContextFunc != nil {
+		return m.PowerOnWithContextFunc(ctx, id, wait)
+	}
+	var r0 *client.Operation[*client.VirtualMachineExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) PowerOff(id string, wait *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error) {
+	results := m.Called("PowerOff", id, wait)
+	if m.PowerOffFunc != nil {
+		return m.PowerOffFunc(id, wait)
+	}
+	var r0 *client.Operation[*client.VirtualMachineExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) PowerOffWithContext(ctx context.Context, id string, wait *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error) {
+	results := m.Called("PowerOffWithContext", ctx, id, wait)
+	if m.PowerOffWithContextFunc != nil {
+		return m.PowerOffWithContextFunc(ctx, id, wait)
+	}
+	var r0 *client.Operation[*client.VirtualMachineExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) Shutdown(id string, wait *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error) {
+	results := m.Called("Shutdown", id, wait)
+	if m.ShutdownFunc != nil {
+		return m.ShutdownFunc(id, wait)
+	}
+	var r0 *client.Operation[*client.VirtualMachineExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) ShutdownWithContext(ctx context.Context, id string, wait *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error) {
+	results := m.Called("ShutdownWithContext", ctx, id, wait)
+	if m.ShutdownWithContextFunc != nil {
+		return m.ShutdownWithContextFunc(ctx, id, wait)
+	}
+	var r0 *client.Operation[*client.VirtualMachineExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) Reboot(id string, wait *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error) {
+	results := m.Called("Reboot", id, wait)
+	if m.RebootFunc != nil {
+		return m.RebootFunc(id, wait)
+	}
+	var r0 *client.Operation[*client.VirtualMachineExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) RebootWithContext(ctx context.Context, id string, wait *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error) {
+	results := m.Called("RebootWithContext", ctx, id, wait)
+	if m.RebootWithContextFunc != nil {
+		return m.RebootWithContextFunc(ctx, id, wait)
+	}
+	var r0 *client.Operation[*client.VirtualMachineExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) Suspend(id string, wait *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error) {
+	results := m.Called("Suspend", id, wait)
+	if m.SuspendFunc != nil {
+		return m.SuspendFunc(id, wait)
+	}
+	var r0 *client.Operation[*client.VirtualMachineExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) SuspendWithContext(ctx context.Context, id string, wait *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error) {
+	results := m.Called("SuspendWithContext", ctx, id, wait)
+	if m.SuspendWithContextFunc != nil {
+		return m.SuspendWithContextFunc(ctx, id, wait)
+	}
+	var r0 *client.Operation[*client.VirtualMachineExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) Reset(id string, wait *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error) {
+	results := m.Called("Reset", id, wait)
+	if m.ResetFunc != nil {
+		return m.ResetFunc(id, wait)
+	}
+	var r0 *client.Operation[*client.VirtualMachineExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) ResetWithContext(ctx context.Context, id string, wait *client.WaitOptions) (*client.Operation[*client.VirtualMachineExt], error) {
+	results := m.Called("ResetWithContext", ctx, id, wait)
+	if m.ResetWithContextFunc != nil {
+		return m.ResetWithContextFunc(ctx, id, wait)
+	}
+	var r0 *client.Operation[*client.VirtualMachineExt]
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) OpenConsole(id string) (*client.OpenConsoleResult, error) {
+	results := m.Called("OpenConsole", id)
+	if m.OpenConsoleFunc != nil {
+		return m.OpenConsoleFunc(id)
+	}
+	var r0 *client.OpenConsoleResult
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
+
+func (m *VirtualServerService) OpenConsoleWithContext(ctx context.Context, id string) (*client.OpenConsoleResult, error) {
+	results := m.Called("OpenConsoleWithContext", ctx, id)
+	if m.OpenConsoleWithContextFunc != nil {
+		return m.OpenConsoleWithContextFunc(ctx, id)
+	}
+	var r0 *client.OpenConsoleResult
+	result(results, 0, &r0)
+	var r1 error
+	result(results, 1, &r1)
+	return r0, r1
+}
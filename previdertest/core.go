@@ -0,0 +1,104 @@
+package previdertest
+
+import (
+	"net/http"
+
+	"github.com/previder/previder-go-sdk/client"
+)
+
+func (s *Server) AddCustomer(customer client.CustomerExt) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if customer.Id == "" {
+		customer.Id = newId()
+	}
+	s.customers.put(customer.Id, &customer)
+	return customer.Id
+}
+
+func (s *Server) Customer(id string) (client.CustomerExt, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	customer, ok := s.customers.get(id)
+	if !ok {
+		return client.CustomerExt{}, false
+	}
+	return *customer, true
+}
+
+func (s *Server) Customers() []client.CustomerExt {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return values(&s.customers, func(customer *client.CustomerExt) client.CustomerExt { return *customer })
+}
+
+func (s *Server) serveCore(req *request, path []string) {
+	if _, ok := match(path, "customer"); ok {
+		switch {
+		case req.is(http.MethodGet):
+			customers := values(&s.customers, func(customer *client.CustomerExt) client.Customer { return customer.Customer })
+			writePage(req, customers, func(customer client.Customer, query string) bool {
+				return matchesQuery(query, customer.Id, customer.Name, customer.AccountName)
+			})
+		case req.is(http.MethodPost):
+			var create client.CustomerCreate
+			if !req.decode(&create) {
+				return
+			}
+			customer := &client.CustomerExt{Customer: client.Customer{Id: newId()}}
+			applyCustomer(customer, create)
+			customer.Audit.CreatedAt = epochMillis()
+			customer.Audit.LastModifiedAt = customer.Audit.CreatedAt
+			s.customers.put(customer.Id, customer)
+			req.write(http.StatusOK, customer.Customer)
+		default:
+			req.methodNotAllowed()
+		}
+		return
+	}
+
+	params, ok := match(path, "customer", "*")
+	if !ok {
+		req.notFound()
+		return
+	}
+	customer, ok := s.customers.get(params[0])
+	if !ok {
+		req.notFound()
+		return
+	}
+	switch {
+	case req.is(http.MethodGet):
+		req.write(http.StatusOK, customer)
+	case req.is(http.MethodPut):
+		var update client.CustomerCreate
+		if !req.decode(&update) {
+			return
+		}
+		applyCustomer(customer, update)
+		customer.Audit.LastModifiedAt = epochMillis()
+		req.write(http.StatusOK, customer.Customer)
+	case req.is(http.MethodDelete):
+		s.customers.delete(customer.Id)
+		req.noContent()
+	default:
+		req.methodNotAllowed()
+	}
+}
+
+func applyCustomer(customer *client.CustomerExt, create client.CustomerCreate) {
+	customer.Name = create.Name
+	customer.AccountName = create.AccountName
+	customer.Address = create.Address
+	customer.AddressNumber = create.AddressNumber
+	customer.AddressSuffix = create.AddressSuffix
+	customer.Zipcode = create.Zipcode
+	customer.City = create.City
+	customer.CountryCode = create.CountryCode
+	customer.Language = create.Language
+	customer.PurchaseOrderNumber = create.PurchaseOrderNumber
+	customer.CocNumber = create.CocNumber
+	customer.Partner = create.Partner
+	customer.HidingPrices = create.HidingPrices
+	customer.InvoiceToPartner = create.InvoiceToPartner
+}
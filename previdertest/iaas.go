@@ -0,0 +1,566 @@
+package previdertest
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/previder/previder-go-sdk/client"
+)
+
+func (s *Server) AddComputeCluster(computeCluster client.ComputeCluster) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.computeClusters = append(s.computeClusters, computeCluster)
+}
+
+func (s *Server) AddTemplate(template client.VirtualMachineTemplate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.templates = append(s.templates, template)
+}
+
+func (s *Server) AddVirtualMachine(vm client.VirtualMachineExt) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if vm.Id == "" {
+		vm.Id = newId()
+	}
+	if vm.State == "" {
+		vm.State = client.VmStatePoweredOn
+	}
+	s.virtualMachines.put(vm.Id, &vm)
+	return vm.Id
+}
+
+func (s *Server) VirtualMachine(id string) (client.VirtualMachineExt, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	vm, ok := s.virtualMachines.get(id)
+	if !ok {
+		return client.VirtualMachineExt{}, false
+	}
+	return *vm, true
+}
+
+func (s *Server) VirtualMachines() []client.VirtualMachineExt {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return values(&s.virtualMachines, func(vm *client.VirtualMachineExt) client.VirtualMachineExt { return *vm })
+}
+
+func (s *Server) AddVirtualNetwork(vn client.VirtualNetwork) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if vn.Id == "" {
+		vn.Id = newId()
+	}
+	if vn.State == "" {
+		vn.State = client.VirtualNetworkStateReady
+	}
+	s.virtualNetworks.put(vn.Id, &vn)
+	return vn.Id
+}
+
+func (s *Server) VirtualNetwork(id string) (client.VirtualNetwork, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	vn, ok := s.virtualNetworks.get(id)
+	if !ok {
+		return client.VirtualNetwork{}, false
+	}
+	return *vn, true
+}
+
+func (s *Server) VirtualNetworks() []client.VirtualNetwork {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return values(&s.virtualNetworks, func(vn *client.VirtualNetwork) client.VirtualNetwork { return *vn })
+}
+
+func (s *Server) AddVirtualFirewall(firewall client.VirtualFirewallExt) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if firewall.Id == "" {
+		firewall.Id = newId()
+	}
+	if firewall.State == "" {
+		firewall.State = ReadyState
+	}
+	s.virtualFirewalls.put(firewall.Id, &provisioned[client.VirtualFirewallExt]{resource: firewall, state: virtualFirewallState})
+	return firewall.Id
+}
+
+func (s *Server) VirtualFirewall(id string) (client.VirtualFirewallExt, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	firewall, ok := s.virtualFirewalls.get(id)
+	if !ok {
+		return client.VirtualFirewallExt{}, false
+	}
+	return firewall.resource, true
+}
+
+func (s *Server) VirtualFirewalls() []client.VirtualFirewallExt {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return values(&s.virtualFirewalls, func(firewall *provisioned[client.VirtualFirewallExt]) client.VirtualFirewallExt {
+		return firewall.resource
+	})
+}
+
+func (s *Server) AddNatRule(firewallId string, rule client.VirtualFirewallNatRule) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if rule.Id == "" {
+		rule.Id = newId()
+	}
+	s.natRuleStore(firewallId).put(rule.Id, &rule)
+	return rule.Id
+}
+
+func (s *Server) NatRules(firewallId string) []client.VirtualFirewallNatRule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return values(s.natRuleStore(firewallId), func(rule *client.VirtualFirewallNatRule) client.VirtualFirewallNatRule { return *rule })
+}
+
+func (s *Server) natRuleStore(firewallId string) *store[client.VirtualFirewallNatRule] {
+	rules, ok := s.natRules[firewallId]
+	if !ok {
+		rules = &store[client.VirtualFirewallNatRule]{}
+		s.natRules[firewallId] = rules
+	}
+	return rules
+}
+
+func (s *Server) serveIaas(req *request, path []string) {
+	if len(path) == 0 {
+		req.notFound()
+		return
+	}
+	switch path[0] {
+	case "task":
+		s.serveTasks(req, path)
+	case "computecluster":
+		if _, ok := match(path, "computecluster"); ok && req.is(http.MethodGet) {
+			req.write(http.StatusOK, append([]client.ComputeCluster{}, s.computeClusters...))
+			return
+		}
+		req.notFound()
+	case "template":
+		if _, ok := match(path, "template"); ok && req.is(http.MethodGet) {
+			req.write(http.StatusOK, append([]client.VirtualMachineTemplate{}, s.templates...))
+			return
+		}
+		req.notFound()
+	case "virtualmachine":
+		s.serveVirtualMachines(req, path)
+	case "virtualnetwork":
+		s.serveVirtualNetworks(req, path)
+	case "virtualfirewall":
+		s.serveVirtualFirewalls(req, path)
+	default:
+		req.notFound()
+	}
+}
+
+func (s *Server) serveVirtualMachines(req *request, path []string) {
+	if _, ok := match(path, "virtualmachine"); ok {
+		switch {
+		case req.is(http.MethodGet):
+			vms := values(&s.virtualMachines, func(vm *client.VirtualMachineExt) client.VirtualMachine { return vm.VirtualMachine })
+			writePage(req, vms, func(vm client.VirtualMachine, query string) bool {
+				return matchesQuery(query, vm.Id, vm.Name, vm.GroupName)
+			})
+		case req.is(http.MethodPost):
+			s.createVirtualMachine(req)
+		default:
+			req.methodNotAllowed()
+		}
+		return
+	}
+
+	if params, ok := match(path, "virtualmachine", "*", "action", "*"); ok && req.is(http.MethodPost) {
+		s.controlVirtualMachine(req, params[0], params[1])
+		return
+	}
+	if params, ok := match(path, "virtualmachine", "*", "console"); ok && req.is(http.MethodPost) {
+		if _, ok := s.virtualMachines.get(params[0]); !ok {
+			req.notFound()
+			return
+		}
+		req.write(http.StatusOK, client.OpenConsoleResult{ConsoleUrl: s.URL + "/console/" + params[0]})
+		return
+	}
+
+	params, ok := match(path, "virtualmachine", "*")
+	if !ok {
+		req.notFound()
+		return
+	}
+	vm, ok := s.virtualMachines.get(params[0])
+	if !ok {
+		req.notFound()
+		return
+	}
+	switch {
+	case req.is(http.MethodGet):
+		req.write(http.StatusOK, vm)
+	case req.is(http.MethodPut):
+		s.updateVirtualMachine(req, vm)
+	case req.is(http.MethodDelete):
+		if vm.TerminationProtectionEnabled {
+			writeError(req.w, req.r, http.StatusConflict, "termination protection is enabled")
+			return
+		}
+		task := s.newTask(client.TaskTypeVirtualMachineDelete, func() {
+			s.virtualMachines.delete(vm.Id)
+		})
+		req.write(http.StatusOK, virtualMachineTask(task, vm))
+	default:
+		req.methodNotAllowed()
+	}
+}
+
+func (s *Server) createVirtualMachine(req *request) {
+	var create client.VirtualMachineCreate
+	if !req.decode(&create) {
+		return
+	}
+	if create.Name == "" {
+		writeError(req.w, req.r, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	vm := create.VirtualMachineExt
+	vm.Id = newId()
+	vm.Template = create.Template
+	vm.GuestId = create.GuestId
+	vm.State = client.VmStateDeploying
+	vm.CreatedAt = epochMillis()
+	vm.LastModifiedAt = vm.CreatedAt
+	s.virtualMachines.put(vm.Id, &vm)
+
+	finalState := client.VmStatePoweredOff
+	if create.PowerOnAfterClone || create.SourceVirtualMachine == "" {
+		finalState = client.VmStatePoweredOn
+	}
+	task := s.newTask(client.TaskTypeVirtualMachineCreate, func() {
+		vm.State = finalState
+	})
+	req.write(http.StatusOK, virtualMachineTask(task, &vm))
+}
+
+func (s *Server) updateVirtualMachine(req *request, vm *client.VirtualMachineExt) {
+	var update client.VirtualMachineUpdate
+	if !req.decode(&update) {
+		return
+	}
+
+	task := s.newTask(client.TaskTypeVirtualMachineUpdate, func() {
+		vm.Name = update.Name
+		vm.Group = update.Group
+		vm.CpuCores = update.CpuCores
+		vm.Memory = update.Memory
+		vm.ComputeCluster = update.ComputeCluster
+		vm.Tags = update.Tags
+		vm.TerminationProtectionEnabled = update.TerminationProtectionEnabled
+		vm.Flavor = update.Flavor
+		vm.LastModifiedAt = epochMillis()
+
+		vm.Disks = nil
+		for _, disk := range update.Disks {
+			if disk.Delete {
+				continue
+			}
+			if disk.Id == "" {
+				disk.Id = newId()
+			}
+			vm.Disks = append(vm.Disks, client.Disk{Id: disk.Id, Size: disk.Size, Uuid: disk.Uuid, Label: disk.Label})
+		}
+		vm.NetworkInterfaces = nil
+		for _, networkInterface := range update.NetworkInterfaces {
+			if networkInterface.Deleted {
+				continue
+			}
+			if networkInterface.Id == "" {
+				networkInterface.Id = newId()
+			}
+			vm.NetworkInterfaces = append(vm.NetworkInterfaces, client.NetworkInterface{
+				Id:        networkInterface.Id,
+				Network:   networkInterface.Network,
+				Connected: networkInterface.Connected,
+				Label:     networkInterface.Label,
+			})
+		}
+	})
+	req.write(http.StatusOK, virtualMachineTask(task, vm))
+}
+
+func (s *Server) controlVirtualMachine(req *request, id string, action string) {
+	vm, ok := s.virtualMachines.get(id)
+	if !ok {
+		req.notFound()
+		return
+	}
+	state := client.VmAction(action).ExpectedState()
+	if state == "" {
+		writeError(req.w, req.r, http.StatusBadRequest, "unknown action "+action)
+		return
+	}
+	task := s.newTask(client.TaskType("VIRTUALMACHINE_"+action), func() {
+		vm.State = state
+	})
+	req.write(http.StatusOK, virtualMachineTask(task, vm))
+}
+
+func virtualMachineTask(task *client.Task, vm *client.VirtualMachineExt) client.VirtualMachineTask {
+	// the task list reports the virtual machine as well
+	task.VirtualMachine = vm.Id
+	return client.VirtualMachineTask{Task: *task, VirtualMachineName: vm.Name}
+}
+
+func (s *Server) serveVirtualNetworks(req *request, path []string) {
+	if _, ok := match(path, "virtualnetwork"); ok {
+		switch {
+		case req.is(http.MethodGet):
+			networks := values(&s.virtualNetworks, func(vn *client.VirtualNetwork) client.VirtualNetwork { return *vn })
+			writePage(req, networks, func(vn client.VirtualNetwork, query string) bool {
+				return matchesQuery(query, vn.Id, vn.Name, vn.GroupName)
+			})
+		case req.is(http.MethodPost):
+			var create client.VirtualNetworkUpdate
+			if !req.decode(&create) {
+				return
+			}
+			vn := &client.VirtualNetwork{
+				Id:    newId(),
+				Name:  create.Name,
+				Type:  create.Type,
+				Group: create.Group,
+				State: client.VirtualNetworkStateNew,
+			}
+			s.virtualNetworks.put(vn.Id, vn)
+			task := s.newTask(client.TaskTypeVirtualNetworkCreate, func() {
+				vn.State = client.VirtualNetworkStateReady
+			})
+			req.write(http.StatusOK, virtualNetworkTask(task, vn))
+		default:
+			req.methodNotAllowed()
+		}
+		return
+	}
+
+	params, ok := match(path, "virtualnetwork", "*")
+	if !ok {
+		req.notFound()
+		return
+	}
+	vn, ok := s.virtualNetworks.get(params[0])
+	if !ok {
+		req.notFound()
+		return
+	}
+	switch {
+	case req.is(http.MethodGet):
+		req.write(http.StatusOK, vn)
+	case req.is(http.MethodPut):
+		var update client.VirtualNetworkUpdate
+		if !req.decode(&update) {
+			return
+		}
+		task := s.newTask(client.TaskTypeVirtualNetworkUpdate, func() {
+			vn.Name = update.Name
+			vn.Type = update.Type
+			vn.Group = update.Group
+		})
+		req.write(http.StatusOK, virtualNetworkTask(task, vn))
+	case req.is(http.MethodDelete):
+		task := s.newTask(client.TaskTypeVirtualNetworkDelete, func() {
+			s.virtualNetworks.delete(vn.Id)
+		})
+		req.write(http.StatusOK, virtualNetworkTask(task, vn))
+	default:
+		req.methodNotAllowed()
+	}
+}
+
+func virtualNetworkTask(task *client.Task, vn *client.VirtualNetwork) client.VirtualNetworkTask {
+	// the task list reports the virtual network as well
+	task.VirtualNetwork = vn.Id
+	return client.VirtualNetworkTask{Task: *task, VirtualNetworkName: vn.Name}
+}
+
+func (s *Server) serveVirtualFirewalls(req *request, path []string) {
+	if _, ok := match(path, "virtualfirewall"); ok {
+		switch {
+		case req.is(http.MethodGet):
+			firewalls := values(&s.virtualFirewalls, func(firewall *provisioned[client.VirtualFirewallExt]) client.VirtualFirewall {
+				return firewall.resource.VirtualFirewall
+			})
+			writePage(req, firewalls, func(firewall client.VirtualFirewall, query string) bool {
+				return matchesQuery(query, firewall.Id, firewall.Name, firewall.GroupName)
+			})
+		case req.is(http.MethodPost):
+			var create client.VirtualFirewallCreate
+			if !req.decode(&create) {
+				return
+			}
+			firewall := client.VirtualFirewallExt{VirtualFirewall: client.VirtualFirewall{Id: newId(), TypeName: create.Type}}
+			applyVirtualFirewallUpdate(&firewall, create.VirtualFirewallUpdate)
+			firewall.Audit.CreatedAt = epochMillis()
+			firewall.Audit.LastModifiedAt = firewall.Audit.CreatedAt
+			s.virtualFirewalls.put(firewall.Id, provision(s, firewall, virtualFirewallState))
+			req.write(http.StatusOK, client.Reference{Id: firewall.Id, Name: firewall.Name, Type: "VirtualFirewall"})
+		default:
+			req.methodNotAllowed()
+		}
+		return
+	}
+
+	if len(path) >= 3 && path[2] == "natrules" {
+		s.serveNatRules(req, path)
+		return
+	}
+
+	params, ok := match(path, "virtualfirewall", "*")
+	if !ok {
+		req.notFound()
+		return
+	}
+	firewall, ok := s.virtualFirewalls.get(params[0])
+	if !ok {
+		req.notFound()
+		return
+	}
+	switch {
+	case req.is(http.MethodGet):
+		req.write(http.StatusOK, firewall.read())
+	case req.is(http.MethodPut):
+		var update client.VirtualFirewallUpdate
+		if !req.decode(&update) {
+			return
+		}
+		applyVirtualFirewallUpdate(&firewall.resource, update)
+		// a later modification time, also for updates within the same millisecond
+		firewall.resource.Audit.LastModifiedAt = max(epochMillis(), firewall.resource.Audit.LastModifiedAt+1)
+		firewall.transition(UpdatingState, s.ProvisioningSteps)
+		req.noContent()
+	case req.is(http.MethodDelete):
+		if firewall.resource.TerminationProtected {
+			writeError(req.w, req.r, http.StatusConflict, "termination protection is enabled")
+			return
+		}
+		s.virtualFirewalls.delete(params[0])
+		delete(s.natRules, params[0])
+		req.noContent()
+	default:
+		req.methodNotAllowed()
+	}
+}
+
+func virtualFirewallState(firewall *client.VirtualFirewallExt) *string {
+	return &firewall.State
+}
+
+func applyVirtualFirewallUpdate(firewall *client.VirtualFirewallExt, update client.VirtualFirewallUpdate) {
+	firewall.Name = update.Name
+	firewall.Group = update.Group
+	firewall.Network = update.Network
+	firewall.LanAddress = update.LanAddress
+	firewall.DhcpEnabled = update.DhcpEnabled
+	firewall.DhcpRangeStart = ipString(update.DhcpRangeStart)
+	firewall.DhcpRangeEnd = ipString(update.DhcpRangeEnd)
+	firewall.LocalDomainName = update.LocalDomainName
+	firewall.DnsEnabled = update.DnsEnabled
+	firewall.Nameservers = nil
+	for _, nameserver := range update.Nameservers {
+		firewall.Nameservers = append(firewall.Nameservers, nameserver.String())
+	}
+	firewall.TerminationProtected = update.TerminationProtected
+	firewall.IcmpWanEnabled = update.IcmpWanEnabled
+	firewall.IcmpLanEnabled = update.IcmpLanEnabled
+}
+
+func ipString(ip net.IP) string {
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
+}
+
+func (s *Server) serveNatRules(req *request, path []string) {
+	if params, ok := match(path, "virtualfirewall", "*", "natrules"); ok {
+		firewall, ok := s.virtualFirewalls.get(params[0])
+		if !ok {
+			req.notFound()
+			return
+		}
+		rules := s.natRuleStore(params[0])
+		switch {
+		case req.is(http.MethodGet):
+			writePage(req, values(rules, func(rule *client.VirtualFirewallNatRule) client.VirtualFirewallNatRule { return *rule }),
+				func(rule client.VirtualFirewallNatRule, query string) bool {
+					return matchesQuery(query, rule.Id, rule.Description)
+				})
+		case req.is(http.MethodPost):
+			var create client.VirtualFirewallNatRuleCreate
+			if !req.decode(&create) {
+				return
+			}
+			rule := &client.VirtualFirewallNatRule{Id: newId()}
+			applyNatRule(rule, firewall.resource, create)
+			rules.put(rule.Id, rule)
+			req.write(http.StatusOK, client.Reference{Id: rule.Id, Name: rule.Description, Type: "VirtualFirewallNatRule"})
+		default:
+			req.methodNotAllowed()
+		}
+		return
+	}
+
+	params, ok := match(path, "virtualfirewall", "*", "natrules", "*")
+	if !ok {
+		req.notFound()
+		return
+	}
+	firewall, ok := s.virtualFirewalls.get(params[0])
+	if !ok {
+		req.notFound()
+		return
+	}
+	rules := s.natRuleStore(params[0])
+	rule, ok := rules.get(params[1])
+	if !ok {
+		req.notFound()
+		return
+	}
+	switch {
+	case req.is(http.MethodGet):
+		req.write(http.StatusOK, rule)
+	case req.is(http.MethodPut):
+		var update client.VirtualFirewallNatRuleCreate
+		if !req.decode(&update) {
+			return
+		}
+		applyNatRule(rule, firewall.resource, update)
+		req.noContent()
+	case req.is(http.MethodDelete):
+		rules.delete(rule.Id)
+		req.noContent()
+	default:
+		req.methodNotAllowed()
+	}
+}
+
+func applyNatRule(rule *client.VirtualFirewallNatRule, firewall client.VirtualFirewallExt, create client.VirtualFirewallNatRuleCreate) {
+	rule.Description = create.Description
+	rule.Active = create.Active
+	rule.Port = create.Port
+	rule.Protocol = create.Protocol
+	rule.Source = create.Source
+	rule.NatDestination = create.NatDestination
+	rule.NatPort = create.NatPort
+	if len(firewall.WanAddress) > 0 {
+		rule.Destination = firewall.WanAddress[0]
+	}
+}
@@ -0,0 +1,218 @@
+package previdertest
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/previder/previder-go-sdk/client"
+)
+
+func (s *Server) AddKubernetesCluster(cluster client.KubernetesClusterExt) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cluster.Id == "" {
+		cluster.Id = newId()
+	}
+	if cluster.State == "" {
+		cluster.State = ReadyState
+	}
+	s.kubernetesClusters.put(cluster.Id, &provisioned[client.KubernetesClusterExt]{resource: cluster, state: kubernetesClusterState})
+	return cluster.Id
+}
+
+func (s *Server) KubernetesCluster(id string) (client.KubernetesClusterExt, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cluster, ok := s.kubernetesClusters.get(id)
+	if !ok {
+		return client.KubernetesClusterExt{}, false
+	}
+	return cluster.resource, true
+}
+
+func (s *Server) KubernetesClusters() []client.KubernetesClusterExt {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return values(&s.kubernetesClusters, func(cluster *provisioned[client.KubernetesClusterExt]) client.KubernetesClusterExt {
+		return cluster.resource
+	})
+}
+
+func (s *Server) AddKubernetesNode(clusterId string, node client.KubernetesClusterNodeInfo) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if node.Id == "" {
+		node.Id = newId()
+	}
+	s.kubernetesNodeStore(clusterId).put(node.Id, &node)
+	return node.Id
+}
+
+func (s *Server) KubernetesNodes(clusterId string) []client.KubernetesClusterNodeInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return values(s.kubernetesNodeStore(clusterId), func(node *client.KubernetesClusterNodeInfo) client.KubernetesClusterNodeInfo { return *node })
+}
+
+func (s *Server) kubernetesNodeStore(clusterId string) *store[client.KubernetesClusterNodeInfo] {
+	nodes, ok := s.kubernetesNodes[clusterId]
+	if !ok {
+		nodes = &store[client.KubernetesClusterNodeInfo]{}
+		s.kubernetesNodes[clusterId] = nodes
+	}
+	return nodes
+}
+
+func kubernetesClusterState(cluster *client.KubernetesClusterExt) *string {
+	return &cluster.State
+}
+
+func (s *Server) serveKubernetes(req *request, path []string) {
+	if _, ok := match(path, "cluster"); ok {
+		switch {
+		case req.is(http.MethodGet):
+			clusters := values(&s.kubernetesClusters, func(cluster *provisioned[client.KubernetesClusterExt]) client.KubernetesCluster {
+				return cluster.resource.KubernetesCluster
+			})
+			writePage(req, clusters, func(cluster client.KubernetesCluster, query string) bool {
+				return matchesQuery(query, cluster.Id, cluster.Name)
+			})
+		case req.is(http.MethodPost):
+			s.createKubernetesCluster(req)
+		default:
+			req.methodNotAllowed()
+		}
+		return
+	}
+
+	if params, ok := match(path, "cluster", "*", "config"); ok && req.is(http.MethodPost) {
+		cluster, ok := s.kubernetesClusters.get(params[0])
+		if !ok {
+			req.notFound()
+			return
+		}
+		var configRequest client.KubernetesClusterKubeConfigRequest
+		if !req.decode(&configRequest) {
+			return
+		}
+		req.write(http.StatusOK, client.KubernetesClusterKubeConfigResponse{Config: kubeConfig(cluster.resource, configRequest.Endpoint)})
+		return
+	}
+
+	if params, ok := match(path, "cluster", "*", "nodes"); ok && req.is(http.MethodGet) {
+		if _, ok := s.kubernetesClusters.get(params[0]); !ok {
+			req.notFound()
+			return
+		}
+		nodes := values(s.kubernetesNodeStore(params[0]), func(node *client.KubernetesClusterNodeInfo) client.KubernetesClusterNodeInfo { return *node })
+		writePage(req, nodes, func(node client.KubernetesClusterNodeInfo, query string) bool {
+			return node.Name == query
+		})
+		return
+	}
+
+	params, ok := match(path, "cluster", "*")
+	if !ok {
+		req.notFound()
+		return
+	}
+	cluster, ok := s.kubernetesClusters.get(params[0])
+	if !ok {
+		req.notFound()
+		return
+	}
+	switch {
+	case req.is(http.MethodGet):
+		req.write(http.StatusOK, cluster.read())
+	case req.is(http.MethodPut):
+		var update client.KubernetesClusterUpdate
+		if !req.decode(&update) {
+			return
+		}
+		applyKubernetesClusterUpdate(&cluster.resource, update)
+		cluster.transition(UpdatingState, s.ProvisioningSteps)
+		req.noContent()
+	case req.is(http.MethodDelete):
+		s.kubernetesClusters.delete(params[0])
+		delete(s.kubernetesNodes, params[0])
+		req.noContent()
+	default:
+		req.methodNotAllowed()
+	}
+}
+
+func (s *Server) createKubernetesCluster(req *request) {
+	var create client.KubernetesClusterCreate
+	if !req.decode(&create) {
+		return
+	}
+	if create.Name == "" {
+		writeError(req.w, req.r, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	cluster := client.KubernetesClusterExt{
+		KubernetesCluster: client.KubernetesCluster{Id: newId()},
+		Vips:              create.Vips,
+		Endpoints:         create.Endpoints,
+		CNI:               create.CNI,
+		Network:           create.Network,
+	}
+	applyKubernetesClusterUpdate(&cluster, create.KubernetesClusterUpdate)
+	s.kubernetesClusters.put(cluster.Id, provision(s, cluster, kubernetesClusterState))
+
+	nodes := s.kubernetesNodeStore(cluster.Id)
+	controlPlanes := 1
+	if create.HighAvailableControlPlane {
+		controlPlanes = 3
+	}
+	for i := 0; i < controlPlanes; i++ {
+		node := &client.KubernetesClusterNodeInfo{Id: newId(), Name: fmt.Sprintf("%s-cp-%d", create.Name, i), ControlPlane: true}
+		nodes.put(node.Id, node)
+	}
+	for i := 0; i < create.MinimalNodes; i++ {
+		node := &client.KubernetesClusterNodeInfo{Id: newId(), Name: fmt.Sprintf("%s-node-%d", create.Name, i)}
+		nodes.put(node.Id, node)
+	}
+
+	req.write(http.StatusOK, client.Reference{Id: cluster.Id, Name: cluster.Name, Type: "KubernetesCluster"})
+}
+
+func applyKubernetesClusterUpdate(cluster *client.KubernetesClusterExt, update client.KubernetesClusterUpdate) {
+	cluster.Name = update.Name
+	if update.Version != "" {
+		cluster.Version = update.Version
+	}
+	cluster.MinimalNodes = update.MinimalNodes
+	cluster.MaximalNodes = update.MaximalNodes
+	cluster.AutoUpdate = update.AutoUpdate
+	cluster.AutoScaleEnabled = update.AutoScaleEnabled
+	cluster.ControlPlaneCpuCores = update.ControlPlaneCpuCores
+	cluster.ControlPlaneMemoryGb = update.ControlPlaneMemoryGb
+	cluster.ControlPlaneStorageGb = update.ControlPlaneStorageGb
+	cluster.NodeCpuCores = update.NodeCpuCores
+	cluster.NodeMemoryGb = update.NodeMemoryGb
+	cluster.NodeStorageGb = update.NodeStorageGb
+	cluster.ComputeCluster = update.ComputeCluster
+	cluster.HighAvailableControlPlane = update.HighAvailableControlPlane
+}
+
+func kubeConfig(cluster client.KubernetesClusterExt, endpoint string) string {
+	return fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: %[1]s
+  cluster:
+    server: https://%[2]s:6443
+contexts:
+- name: %[1]s
+  context:
+    cluster: %[1]s
+    user: %[1]s-admin
+current-context: %[1]s
+users:
+- name: %[1]s-admin
+  user:
+    token: previdertest
+`, cluster.Name, endpoint)
+}
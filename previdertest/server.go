@@ -0,0 +1,414 @@
+// Package previdertest provides an in-memory emulation of the Previder Portal
+// API for testing code built on the client package without the real portal.
+//
+//	server := previdertest.NewServer()
+//	defer server.Close()
+//	vmId := server.AddVirtualMachine(client.VirtualMachineExt{VirtualMachine: client.VirtualMachine{Name: "web01"}})
+//	previderClient, err := server.NewClient()
+package previdertest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/previder/previder-go-sdk/client"
+)
+
+const (
+	DefaultToken      = "previdertest-token"
+	DefaultTaskSteps  = 2
+	defaultPageSize   = 20
+	authTokenHeader   = "X-Auth-Token"
+	customerIdHeader  = "X-CustomerId"
+	jsonContentHeader = "application/json"
+)
+
+// States of the Kubernetes clusters, STaaS environments and virtual firewalls of the Server.
+// The API does not document these states, so they are the states of this emulation only.
+// Set client.WaitOptions.ReadyState to ReadyState to wait for these resources.
+const (
+	ProvisioningState = "NEW"
+	UpdatingState     = "UPDATING"
+	ReadyState        = "READY"
+)
+
+// Server is a TLS httptest server emulating the v2 iaas, kubernetes, storage/staas
+// and core endpoints. All state is kept in memory and can be seeded and inspected
+// through its methods, which are safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	// Token is the only accepted X-Auth-Token, requests with another token get a 401
+	Token string
+	// TaskSteps is the number of times a task is polled before it completes
+	TaskSteps int
+	// ProvisioningSteps is the number of times a Kubernetes cluster, STaaS environment
+	// or virtual firewall is read before it reaches its ready state, after its creation
+	// and after every update.
+	ProvisioningSteps int
+
+	mu                 sync.Mutex
+	requests           []Request
+	failures           []failure
+	tasks              store[taskState]
+	computeClusters    []client.ComputeCluster
+	templates          []client.VirtualMachineTemplate
+	virtualMachines    store[client.VirtualMachineExt]
+	virtualNetworks    store[client.VirtualNetwork]
+	virtualFirewalls   store[provisioned[client.VirtualFirewallExt]]
+	natRules           map[string]*store[client.VirtualFirewallNatRule]
+	kubernetesClusters store[provisioned[client.KubernetesClusterExt]]
+	kubernetesNodes    map[string]*store[client.KubernetesClusterNodeInfo]
+	staasEnvironments  store[provisioned[client.STaaSEnvironmentExt]]
+	customers          store[client.CustomerExt]
+}
+
+// Request is a request received by the Server
+type Request struct {
+	Method string
+	// Path is relative to the API base, e.g. v2/iaas/virtualmachine
+	Path       string
+	Query      string
+	CustomerId string
+	Body       []byte
+}
+
+type failure struct {
+	method string
+	path   string
+	status int
+}
+
+// provisioned is a resource without tasks, that reaches its ready state after a number of reads
+type provisioned[T any] struct {
+	resource  T
+	readsLeft int
+	state     func(*T) *string
+}
+
+func provision[T any](s *Server, resource T, state func(*T) *string) *provisioned[T] {
+	p := &provisioned[T]{resource: resource, state: state}
+	p.transition(ProvisioningState, s.ProvisioningSteps)
+	return p
+}
+
+// transition puts the resource in the state until it was read the number of steps
+func (p *provisioned[T]) transition(state string, steps int) {
+	p.readsLeft = steps
+	*p.state(&p.resource) = state
+	if p.readsLeft <= 0 {
+		*p.state(&p.resource) = ReadyState
+	}
+}
+
+func (p *provisioned[T]) read() *T {
+	if p.readsLeft > 0 {
+		p.readsLeft--
+		if p.readsLeft == 0 {
+			*p.state(&p.resource) = ReadyState
+		}
+	}
+	return &p.resource
+}
+
+func NewServer() *Server {
+	s := &Server{
+		Token:             DefaultToken,
+		TaskSteps:         DefaultTaskSteps,
+		ProvisioningSteps: DefaultTaskSteps,
+		natRules:          map[string]*store[client.VirtualFirewallNatRule]{},
+		kubernetesNodes:   map[string]*store[client.KubernetesClusterNodeInfo]{},
+	}
+	s.Server = httptest.NewTLSServer(s)
+	return s
+}
+
+// ClientOptions returns options for client.New pointing at this server
+func (s *Server) ClientOptions() *client.ClientOptions {
+	return &client.ClientOptions{
+		Token:      s.Token,
+		BaseUrl:    s.URL + "/api/",
+		HttpClient: s.Client(),
+	}
+}
+
+func (s *Server) NewClient() (*client.PreviderClient, error) {
+	return client.New(s.ClientOptions())
+}
+
+// Requests returns all requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// FailNext makes the next request matching method and path fail with the given
+// status. An empty method matches any method. Path is relative to the API base,
+// e.g. v2/iaas/virtualmachine.
+func (s *Server) FailNext(method string, path string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, failure{method: method, path: strings.Join(splitPath(path), "/"), status: status})
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := splitPath(r.URL.Path)
+	if len(path) == 0 || path[0] != "api" {
+		writeError(w, r, http.StatusNotFound, "no such endpoint")
+		return
+	}
+	path = path[1:]
+
+	body, _ := io.ReadAll(r.Body)
+	s.requests = append(s.requests, Request{
+		Method:     r.Method,
+		Path:       strings.Join(path, "/"),
+		Query:      r.URL.RawQuery,
+		CustomerId: r.Header.Get(customerIdHeader),
+		Body:       body,
+	})
+
+	if r.Header.Get(authTokenHeader) != s.Token {
+		writeError(w, r, http.StatusUnauthorized, "invalid token")
+		return
+	}
+	if status, ok := s.takeFailure(r.Method, strings.Join(path, "/")); ok {
+		writeError(w, r, status, http.StatusText(status))
+		return
+	}
+
+	req := &request{w: w, r: r, body: body}
+	switch {
+	case len(path) == 0 && r.Method == http.MethodGet:
+		req.write(http.StatusOK, client.ApiInfo{Name: "previdertest", Version: "2"})
+	case len(path) >= 2 && path[0] == "v2" && path[1] == "iaas":
+		s.serveIaas(req, path[2:])
+	case len(path) >= 2 && path[0] == "v2" && path[1] == "kubernetes":
+		s.serveKubernetes(req, path[2:])
+	case len(path) >= 3 && path[0] == "v2" && path[1] == "storage" && path[2] == "staas":
+		s.serveSTaaS(req, path[3:])
+	case len(path) >= 2 && path[0] == "v2" && path[1] == "core":
+		s.serveCore(req, path[2:])
+	default:
+		req.notFound()
+	}
+}
+
+func (s *Server) takeFailure(method string, path string) (int, bool) {
+	for i, f := range s.failures {
+		if (f.method == "" || f.method == method) && f.path == path {
+			s.failures = append(s.failures[:i], s.failures[i+1:]...)
+			return f.status, true
+		}
+	}
+	return 0, false
+}
+
+type request struct {
+	w    http.ResponseWriter
+	r    *http.Request
+	body []byte
+}
+
+func (req *request) is(method string) bool {
+	return req.r.Method == method
+}
+
+func (req *request) decode(v interface{}) bool {
+	if err := json.Unmarshal(req.body, v); err != nil {
+		writeError(req.w, req.r, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func (req *request) write(status int, v interface{}) {
+	req.w.Header().Set("Content-Type", jsonContentHeader)
+	req.w.WriteHeader(status)
+	if v != nil {
+		_ = json.NewEncoder(req.w).Encode(v)
+	}
+}
+
+func (req *request) noContent() {
+	req.w.WriteHeader(http.StatusNoContent)
+}
+
+func (req *request) notFound() {
+	writeError(req.w, req.r, http.StatusNotFound, "not found")
+}
+
+func (req *request) methodNotAllowed() {
+	writeError(req.w, req.r, http.StatusMethodNotAllowed, "method not allowed")
+}
+
+type errorResponse struct {
+	Timestamp int64  `json:"timestamp"`
+	Status    int    `json:"status"`
+	Error     string `json:"error"`
+	Message   string `json:"message"`
+	Path      string `json:"path"`
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, status int, message string) {
+	w.Header().Set("Content-Type", jsonContentHeader)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(errorResponse{
+		Timestamp: time.Now().UnixMilli(),
+		Status:    status,
+		Error:     http.StatusText(status),
+		Message:   message,
+		Path:      r.URL.Path,
+	})
+}
+
+type pageResponse[T any] struct {
+	TotalPages       int `json:"totalPages"`
+	TotalElements    int `json:"totalElements"`
+	NumberOfElements int `json:"numberOfElements"`
+	Size             int `json:"size"`
+	Number           int `json:"number"`
+	Content          []T `json:"content"`
+}
+
+// writePage writes the page selected by the page and size parameters, keeping
+// only the items for which match returns true for the query parameter
+func writePage[T any](req *request, items []T, match func(item T, query string) bool) {
+	q := req.r.URL.Query()
+	size, err := strconv.Atoi(q.Get("size"))
+	if err != nil || size <= 0 {
+		size = defaultPageSize
+	}
+	number, err := strconv.Atoi(q.Get("page"))
+	if err != nil || number < 0 {
+		number = 0
+	}
+
+	var filtered []T
+	for _, item := range items {
+		if query := q.Get("query"); query == "" || match(item, query) {
+			filtered = append(filtered, item)
+		}
+	}
+
+	page := pageResponse[T]{
+		TotalPages:    (len(filtered) + size - 1) / size,
+		TotalElements: len(filtered),
+		Size:          size,
+		Number:        number,
+		Content:       []T{},
+	}
+	if start := number * size; start < len(filtered) {
+		page.Content = filtered[start:min(start+size, len(filtered))]
+	}
+	page.NumberOfElements = len(page.Content)
+	req.write(http.StatusOK, page)
+}
+
+// matchesQuery does a case-insensitive free-text match of the query against the given fields
+func matchesQuery(query string, fields ...string) bool {
+	query = strings.ToLower(query)
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), query) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitPath(path string) []string {
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// match reports whether path matches the pattern, where * matches any segment.
+// The segments matched by * are returned.
+func match(path []string, pattern ...string) ([]string, bool) {
+	if len(path) != len(pattern) {
+		return nil, false
+	}
+	var params []string
+	for i, segment := range pattern {
+		switch {
+		case segment == "*":
+			params = append(params, path[i])
+		case segment != path[i]:
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func newId() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+type store[T any] struct {
+	ids   []string
+	items map[string]*T
+}
+
+func (s *store[T]) put(id string, item *T) {
+	if s.items == nil {
+		s.items = map[string]*T{}
+	}
+	if _, ok := s.items[id]; !ok {
+		s.ids = append(s.ids, id)
+	}
+	s.items[id] = item
+}
+
+func (s *store[T]) get(id string) (*T, bool) {
+	item, ok := s.items[id]
+	return item, ok
+}
+
+func (s *store[T]) delete(id string) {
+	if _, ok := s.items[id]; !ok {
+		return
+	}
+	delete(s.items, id)
+	for i, existing := range s.ids {
+		if existing == id {
+			s.ids = append(s.ids[:i], s.ids[i+1:]...)
+			break
+		}
+	}
+}
+
+func (s *store[T]) list() []*T {
+	items := make([]*T, 0, len(s.ids))
+	for _, id := range s.ids {
+		items = append(items, s.items[id])
+	}
+	return items
+}
+
+// values returns copies of all items, converted with f
+func values[T any, V any](s *store[T], f func(*T) V) []V {
+	items := s.list()
+	result := make([]V, len(items))
+	for i, item := range items {
+		result[i] = f(item)
+	}
+	return result
+}
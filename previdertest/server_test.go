@@ -0,0 +1,326 @@
+package previdertest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/previder/previder-go-sdk/client"
+	"github.com/previder/previder-go-sdk/previdertest"
+)
+
+// fastWait polls the server without noticeable delays
+var fastWait = client.WaitOptions{Timeout: 5 * time.Second, InitialInterval: time.Millisecond, MaxPollErrors: 3}
+
+func newServer(t *testing.T) (*previdertest.Server, *client.PreviderClient) {
+	t.Helper()
+	server := previdertest.NewServer()
+	t.Cleanup(server.Close)
+	options := server.ClientOptions()
+	options.RetryPolicy = &client.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	previderClient, err := client.New(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server, previderClient
+}
+
+func TestVirtualMachineLifecycle(t *testing.T) {
+	server, previderClient := newServer(t)
+	ctx := context.Background()
+
+	create := &client.VirtualMachineCreate{VirtualMachineExt: client.VirtualMachineExt{VirtualMachine: client.VirtualMachine{Name: "web01"}}}
+	operation, err := previderClient.VirtualServer.CreateWithContext(ctx, create)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if operation.Task == nil || operation.Done() {
+		t.Fatalf("expected a pending operation with a task, got %+v", operation)
+	}
+	if _, err := operation.Result(); !errors.Is(err, client.ErrOperationPending) {
+		t.Errorf("expected ErrOperationPending before the wait, got %v", err)
+	}
+	vm, err := operation.WaitWithOptions(ctx, fastWait)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.Id != operation.ResourceId || vm.Name != "web01" || vm.State != client.VmStatePoweredOn || !operation.Done() {
+		t.Errorf("expected the powered on virtual machine, got %+v", vm)
+	}
+
+	update := &client.VirtualMachineUpdate{VirtualMachine: client.VirtualMachine{Name: "web02"}}
+	updated, err := previderClient.VirtualServer.UpdateWithContext(ctx, vm.Id, update)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm, err = updated.WaitWithOptions(ctx, fastWait); err != nil || vm.Name != "web02" {
+		t.Fatalf("expected the renamed virtual machine, got %+v, %v", vm, err)
+	}
+
+	powerOff, err := previderClient.VirtualServer.PowerOffWithContext(ctx, vm.Id, &fastWait)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm, err = powerOff.Result(); err != nil || vm.State != client.VmStatePoweredOff {
+		t.Errorf("expected the powered off virtual machine, got %+v, %v", vm, err)
+	}
+
+	deleted, err := previderClient.VirtualServer.DeleteWithContext(ctx, vm.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := deleted.WaitWithOptions(ctx, fastWait); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := server.VirtualMachine(vm.Id); ok {
+		t.Error("expected the virtual machine to be deleted")
+	}
+	if _, err := previderClient.VirtualServer.GetWithContext(ctx, vm.Id); !errors.Is(err, client.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestPagination(t *testing.T) {
+	server, previderClient := newServer(t)
+	ctx := context.Background()
+	for _, name := range []string{"web01", "web02", "db01", "db02", "db03"} {
+		server.AddVirtualMachine(client.VirtualMachineExt{VirtualMachine: client.VirtualMachine{Name: name}})
+	}
+
+	page, err := previderClient.VirtualServer.PageWithContext(ctx, client.NewPageRequest().WithSize(2).WithPage(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.TotalElements != 5 || page.TotalPages != 3 || len(page.Content) != 2 || page.Content[0].Name != "db01" || !page.HasNext() {
+		t.Errorf("unexpected second page %+v", page)
+	}
+
+	page, err = previderClient.VirtualServer.PageWithContext(ctx, client.NewPageRequest().WithQuery("web"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.TotalElements != 2 {
+		t.Errorf("expected 2 virtual machines matching the query, got %+v", page)
+	}
+
+	before := len(server.Requests())
+	var names []string
+	for vm, err := range previderClient.VirtualServer.All(ctx, client.NewPageRequest().WithSize(2)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, vm.Name)
+	}
+	if len(names) != 5 || names[4] != "db03" {
+		t.Errorf("expected all 5 virtual machines in order, got %v", names)
+	}
+	if requests := len(server.Requests()) - before; requests != 3 {
+		t.Errorf("expected 3 page requests, got %d", requests)
+	}
+
+	// stopping early must not request further pages
+	before = len(server.Requests())
+	for range previderClient.VirtualServer.All(ctx, client.NewPageRequest().WithSize(2)) {
+		break
+	}
+	if requests := len(server.Requests()) - before; requests != 1 {
+		t.Errorf("expected 1 page request, got %d", requests)
+	}
+}
+
+func TestTaskProgress(t *testing.T) {
+	server, previderClient := newServer(t)
+	server.TaskSteps = 4
+	ctx := context.Background()
+	vmId := server.AddVirtualMachine(client.VirtualMachineExt{VirtualMachine: client.VirtualMachine{Name: "web01"}})
+
+	task, err := previderClient.VirtualServer.ControlWithContext(ctx, vmId, client.VmActionReboot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var statuses []client.TaskStatus
+	var progress []int
+	_, err = previderClient.Task.WaitForWithProgress(ctx, task.Id, fastWait, func(update client.TaskProgress) {
+		statuses = append(statuses, update.Status)
+		progress = append(progress, update.Progress)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) < 2 || statuses[0] != client.TaskStatusRunning || statuses[len(statuses)-1] != client.TaskStatusSucceeded {
+		t.Errorf("expected running updates ending in succeeded, got %v", statuses)
+	}
+	for i := 1; i < len(progress); i++ {
+		if progress[i] < progress[i-1] {
+			t.Errorf("expected increasing progress, got %v", progress)
+		}
+	}
+
+	watched, err := previderClient.VirtualServer.ControlWithContext(ctx, vmId, client.VmActionPowerOff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var last client.TaskProgress
+	for update := range previderClient.Task.Watch(ctx, watched.Id, fastWait) {
+		last = update
+	}
+	if last.Err != nil || last.Status != client.TaskStatusSucceeded {
+		t.Errorf("expected Watch to end with the succeeded task, got %+v", last)
+	}
+}
+
+func TestTaskHistory(t *testing.T) {
+	server, previderClient := newServer(t)
+	ctx := context.Background()
+	vmId := server.AddVirtualMachine(client.VirtualMachineExt{VirtualMachine: client.VirtualMachine{Name: "web01"}})
+	otherId := server.AddVirtualMachine(client.VirtualMachineExt{VirtualMachine: client.VirtualMachine{Name: "web02"}})
+
+	for _, id := range []string{vmId, otherId, vmId} {
+		if _, err := previderClient.VirtualServer.ControlWithContext(ctx, id, client.VmActionReboot); err != nil {
+			t.Fatal(err)
+		}
+	}
+	filter := client.TaskFilter{Resource: vmId, Types: []client.TaskType{client.TaskTypeVirtualMachineReboot}}
+	page, err := previderClient.Task.HistoryWithContext(ctx, filter, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.TotalElements != 2 || page.TotalPages != 2 || len(page.Content) != 1 || page.Content[0].VirtualMachine != vmId {
+		t.Errorf("expected the first of 2 reboots of the virtual machine, got %+v", page)
+	}
+}
+
+func TestWaitForMany(t *testing.T) {
+	server, previderClient := newServer(t)
+	ctx := context.Background()
+	var ids []string
+	for _, name := range []string{"web01", "web02", "web03"} {
+		vmId := server.AddVirtualMachine(client.VirtualMachineExt{VirtualMachine: client.VirtualMachine{Name: name}})
+		task, err := previderClient.VirtualServer.ControlWithContext(ctx, vmId, client.VmActionShutdown)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, task.Id)
+	}
+	if !server.FailTask(ids[1], "guest tools not running") {
+		t.Fatal("expected the task to be pending")
+	}
+
+	results, err := previderClient.Task.WaitForAll(ctx, ids, fastWait)
+	var failed *client.TaskFailedError
+	if !errors.As(err, &failed) || failed.ErrorMessage != "guest tools not running" {
+		t.Fatalf("expected the failed task in the error, got %v", err)
+	}
+	for i, result := range results {
+		if result.Id != ids[i] || (i == 1) != (result.Err != nil) {
+			t.Errorf("unexpected result %d: %+v", i, result)
+		}
+	}
+
+	result, err := previderClient.Task.WaitForAny(ctx, []string{ids[0], ids[2]}, fastWait)
+	if err != nil || (result.Id != ids[0] && result.Id != ids[2]) {
+		t.Errorf("expected one of the succeeded tasks, got %+v, %v", result, err)
+	}
+}
+
+func TestStateOperations(t *testing.T) {
+	_, previderClient := newServer(t)
+	ctx := context.Background()
+
+	create := client.KubernetesClusterCreate{KubernetesClusterUpdate: client.KubernetesClusterUpdate{Name: "cluster", MinimalNodes: 2}}
+	operation, err := previderClient.KubernetesCluster.CreateWithContext(ctx, create)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := operation.Wait(ctx); !errors.Is(err, client.ErrNoReadyState) || operation.Done() {
+		t.Fatalf("expected ErrNoReadyState without a ready state, got %v", err)
+	}
+	untilReady := fastWait
+	untilReady.ReadyState = previdertest.ReadyState
+	cluster, err := operation.WaitWithOptions(ctx, untilReady)
+	if err != nil || cluster.State != previdertest.ReadyState {
+		t.Fatalf("expected the ready cluster, got %+v, %v", cluster, err)
+	}
+
+	update := create.KubernetesClusterUpdate
+	update.MinimalNodes = 3
+	updated, err := previderClient.KubernetesCluster.UpdateWithContext(ctx, cluster.Id, update)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cluster, err = updated.WaitWithOptions(ctx, untilReady); err != nil || cluster.MinimalNodes != 3 {
+		t.Fatalf("expected the updated cluster, got %+v, %v", cluster, err)
+	}
+
+	if cluster, err = previderClient.KubernetesCluster.WaitForState(ctx, cluster.Id, previdertest.ReadyState, fastWait); err != nil || cluster.State != previdertest.ReadyState {
+		t.Errorf("expected the ready cluster, got %+v, %v", cluster, err)
+	}
+
+	deleted, err := previderClient.KubernetesCluster.DeleteWithContext(ctx, cluster.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := deleted.WaitWithOptions(ctx, fastWait); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFailNext(t *testing.T) {
+	server, previderClient := newServer(t)
+	ctx := context.Background()
+	vmId := server.AddVirtualMachine(client.VirtualMachineExt{VirtualMachine: client.VirtualMachine{Name: "web01"}})
+
+	server.FailNext(http.MethodGet, "v2/iaas/virtualmachine/"+vmId, http.StatusServiceUnavailable)
+	if _, err := previderClient.VirtualServer.GetWithContext(ctx, vmId); err != nil {
+		t.Errorf("expected the 503 to be retried, got %v", err)
+	}
+
+	server.FailNext("", "v2/iaas/virtualmachine/"+vmId, http.StatusConflict)
+	_, err := previderClient.VirtualServer.GetWithContext(ctx, vmId)
+	var apiError *client.ApiError
+	if !errors.As(err, &apiError) || !errors.Is(err, client.ErrConflict) || apiError.Method != http.MethodGet || apiError.ErrorCode != "Conflict" {
+		t.Errorf("expected a conflict ApiError, got %v", err)
+	}
+
+	if _, err := previderClient.VirtualServer.GetWithContext(ctx, vmId); err != nil {
+		t.Errorf("expected the failure to be used once, got %v", err)
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	server, _ := newServer(t)
+	options := server.ClientOptions()
+	options.Token = "wrong-token"
+	previderClient, err := client.New(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = previderClient.VirtualServer.GetWithContext(context.Background(), "1")
+	if !errors.Is(err, client.ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+	if requests := server.Requests(); len(requests) != 1 {
+		t.Errorf("expected a 401 not to be retried, got %d requests", len(requests))
+	}
+}
+
+func TestCustomerView(t *testing.T) {
+	server, previderClient := newServer(t)
+	view, err := previderClient.WithCustomer("0123456789abcdef01234567")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := view.VirtualServer.PageWithContext(context.Background(), client.NewPageRequest()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := previderClient.VirtualServer.PageWithContext(context.Background(), client.NewPageRequest()); err != nil {
+		t.Fatal(err)
+	}
+	requests := server.Requests()
+	if requests[0].CustomerId != "0123456789abcdef01234567" || requests[1].CustomerId != "" {
+		t.Errorf("expected only the view to send the customer id, got %+v", requests)
+	}
+}
@@ -0,0 +1,184 @@
+package previdertest
+
+import (
+	"net/http"
+
+	"github.com/previder/previder-go-sdk/client"
+)
+
+func (s *Server) AddSTaaSEnvironment(environment client.STaaSEnvironmentExt) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if environment.Id == "" {
+		environment.Id = newId()
+	}
+	if environment.State == "" {
+		environment.State = ReadyState
+	}
+	s.staasEnvironments.put(environment.Id, &provisioned[client.STaaSEnvironmentExt]{resource: environment, state: staasEnvironmentState})
+	return environment.Id
+}
+
+func (s *Server) STaaSEnvironment(id string) (client.STaaSEnvironmentExt, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	environment, ok := s.staasEnvironments.get(id)
+	if !ok {
+		return client.STaaSEnvironmentExt{}, false
+	}
+	return environment.resource, true
+}
+
+func (s *Server) STaaSEnvironments() []client.STaaSEnvironmentExt {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return values(&s.staasEnvironments, func(environment *provisioned[client.STaaSEnvironmentExt]) client.STaaSEnvironmentExt {
+		return environment.resource
+	})
+}
+
+func staasEnvironmentState(environment *client.STaaSEnvironmentExt) *string {
+	return &environment.State
+}
+
+func (s *Server) serveSTaaS(req *request, path []string) {
+	if _, ok := match(path, "environment"); ok {
+		switch {
+		case req.is(http.MethodGet):
+			environments := values(&s.staasEnvironments, func(environment *provisioned[client.STaaSEnvironmentExt]) client.STaaSEnvironment {
+				return environment.resource.STaaSEnvironment
+			})
+			writePage(req, environments, func(environment client.STaaSEnvironment, query string) bool {
+				return matchesQuery(query, environment.Id, environment.Name)
+			})
+		case req.is(http.MethodPost):
+			var create client.STaaSEnvironmentCreate
+			if !req.decode(&create) {
+				return
+			}
+			environment := client.STaaSEnvironmentExt{STaaSEnvironment: create.STaaSEnvironment, Windows: create.Windows}
+			environment.Id = newId()
+			environment.Type = create.Type
+			environment.Cluster = create.Cluster
+			s.staasEnvironments.put(environment.Id, provision(s, environment, staasEnvironmentState))
+			req.write(http.StatusOK, client.Reference{Id: environment.Id, Name: environment.Name, Type: "STaaSEnvironment"})
+		default:
+			req.methodNotAllowed()
+		}
+		return
+	}
+
+	if len(path) < 2 || path[0] != "environment" {
+		req.notFound()
+		return
+	}
+	environment, ok := s.staasEnvironments.get(path[1])
+	if !ok {
+		req.notFound()
+		return
+	}
+	env := &environment.resource
+
+	if _, ok := match(path, "environment", "*"); ok {
+		switch {
+		case req.is(http.MethodGet):
+			req.write(http.StatusOK, environment.read())
+		case req.is(http.MethodPut):
+			var update client.STaaSEnvironmentUpdate
+			if !req.decode(&update) {
+				return
+			}
+			env.Name = update.Name
+			env.Windows = update.Windows
+			environment.transition(UpdatingState, s.ProvisioningSteps)
+			req.noContent()
+		case req.is(http.MethodDelete):
+			s.staasEnvironments.delete(env.Id)
+			req.noContent()
+		default:
+			req.methodNotAllowed()
+		}
+		return
+	}
+
+	if _, ok := match(path, "environment", "*", "volume"); ok && req.is(http.MethodPost) {
+		var create client.STaaSVolumeCreate
+		if !req.decode(&create) {
+			return
+		}
+		volume := client.STaaSVolume{Id: newId(), State: ReadyState}
+		applySTaaSVolume(&volume, create)
+		env.Volumes = append(env.Volumes, volume)
+		req.noContent()
+		return
+	}
+
+	if params, ok := match(path, "environment", "*", "volume", "*"); ok {
+		i := indexOf(env.Volumes, func(volume client.STaaSVolume) bool { return volume.Id == params[1] })
+		if i < 0 {
+			req.notFound()
+			return
+		}
+		switch {
+		case req.is(http.MethodPut):
+			var update client.STaaSVolumeUpdate
+			if !req.decode(&update) {
+				return
+			}
+			applySTaaSVolume(&env.Volumes[i], update.STaaSVolumeCreate)
+			req.noContent()
+		case req.is(http.MethodDelete):
+			env.Volumes = append(env.Volumes[:i], env.Volumes[i+1:]...)
+			req.noContent()
+		default:
+			req.methodNotAllowed()
+		}
+		return
+	}
+
+	if _, ok := match(path, "environment", "*", "network"); ok && req.is(http.MethodPost) {
+		var create client.STaaSNetworkCreate
+		if !req.decode(&create) {
+			return
+		}
+		network := client.STaaSNetwork{Id: newId(), State: ReadyState, NetworkId: create.Network, Cidr: create.Cidr}
+		if vn, ok := s.virtualNetworks.get(create.Network); ok {
+			network.NetworkName = vn.Name
+		}
+		env.Networks = append(env.Networks, network)
+		req.noContent()
+		return
+	}
+
+	if params, ok := match(path, "environment", "*", "network", "*"); ok && req.is(http.MethodDelete) {
+		i := indexOf(env.Networks, func(network client.STaaSNetwork) bool { return network.Id == params[1] })
+		if i < 0 {
+			req.notFound()
+			return
+		}
+		env.Networks = append(env.Networks[:i], env.Networks[i+1:]...)
+		req.noContent()
+		return
+	}
+
+	req.notFound()
+}
+
+func applySTaaSVolume(volume *client.STaaSVolume, create client.STaaSVolumeCreate) {
+	volume.Name = create.Name
+	volume.Type = create.Type
+	volume.SynchronousEnvironmentId = create.SynchronousEnvironmentId
+	volume.SynchronousEnvironmentName = create.SynchronousEnvironmentName
+	volume.AllowedIpsRo = create.AllowedIpsRo
+	volume.AllowedIpsRw = create.AllowedIpsRw
+	volume.SizeMb = create.SizeMb
+}
+
+func indexOf[T any](items []T, f func(T) bool) int {
+	for i, item := range items {
+		if f(item) {
+			return i
+		}
+	}
+	return -1
+}
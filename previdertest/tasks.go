@@ -0,0 +1,147 @@
+package previdertest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/previder/previder-go-sdk/client"
+)
+
+type taskState struct {
+	task      client.Task
+	pollsLeft int
+	// onComplete applies the result of a successful task to the resources, it runs with the server lock held
+	onComplete func()
+}
+
+// AddTask seeds a task. Tasks that are not completed progress like any other task.
+func (s *Server) AddTask(task client.Task) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if task.Id == "" {
+		task.Id = newId()
+	}
+	s.tasks.put(task.Id, &taskState{task: task, pollsLeft: s.TaskSteps})
+	return task.Id
+}
+
+func (s *Server) Task(id string) (client.Task, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.tasks.get(id)
+	if !ok {
+		return client.Task{}, false
+	}
+	return state.task, true
+}
+
+func (s *Server) Tasks() []client.Task {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return values(&s.tasks, func(state *taskState) client.Task { return state.task })
+}
+
+// CompleteTasks successfully completes all pending tasks
+func (s *Server) CompleteTasks() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, state := range s.tasks.list() {
+		s.completeTask(state, "")
+	}
+}
+
+// FailTask completes a pending task unsuccessfully, without applying its changes
+func (s *Server) FailTask(id string, errorMessage string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.tasks.get(id)
+	if !ok || state.task.Completed {
+		return false
+	}
+	s.completeTask(state, errorMessage)
+	return true
+}
+
+func (s *Server) newTask(taskType client.TaskType, onComplete func()) *client.Task {
+	now := epochMillis()
+	state := &taskState{
+		task: client.Task{
+			Id:       newId(),
+			User:     "previdertest",
+			TaskType: taskType,
+			TaskDate: &now,
+		},
+		pollsLeft:  s.TaskSteps,
+		onComplete: onComplete,
+	}
+	s.tasks.put(state.task.Id, state)
+	if s.TaskSteps <= 0 {
+		s.completeTask(state, "")
+	}
+	return &state.task
+}
+
+func (s *Server) pollTask(state *taskState) {
+	if state.task.Completed {
+		return
+	}
+	if state.task.StartedDate == nil {
+		now := epochMillis()
+		state.task.StartedDate = &now
+	}
+	state.pollsLeft--
+	if state.pollsLeft <= 0 {
+		s.completeTask(state, "")
+		return
+	}
+	state.task.Progress = 100 * (s.TaskSteps - state.pollsLeft) / s.TaskSteps
+}
+
+func (s *Server) completeTask(state *taskState, errorMessage string) {
+	if state.task.Completed {
+		return
+	}
+	now := epochMillis()
+	if state.task.StartedDate == nil {
+		state.task.StartedDate = &now
+	}
+	state.task.CompletedDate = &now
+	state.task.Completed = true
+	state.task.Progress = 100
+	if errorMessage != "" {
+		state.task.Error = true
+		state.task.ErrorMessage = errorMessage
+		return
+	}
+	state.task.Success = true
+	if state.onComplete != nil {
+		state.onComplete()
+	}
+}
+
+func (s *Server) serveTasks(req *request, path []string) {
+	if _, ok := match(path, "task"); ok && req.is(http.MethodGet) {
+		tasks := make([]client.Task, 0)
+		for _, state := range s.tasks.list() {
+			s.pollTask(state)
+			tasks = append(tasks, state.task)
+		}
+		req.write(http.StatusOK, tasks)
+		return
+	}
+	if params, ok := match(path, "task", "*"); ok && req.is(http.MethodGet) {
+		state, ok := s.tasks.get(params[0])
+		if !ok {
+			req.notFound()
+			return
+		}
+		s.pollTask(state)
+		req.write(http.StatusOK, state.task)
+		return
+	}
+	req.notFound()
+}
+
+func epochMillis() int {
+	return int(time.Now().UnixMilli())
+}
@@ -0,0 +1,100 @@
+// Package prommetrics records the API calls of a client.PreviderClient as Prometheus metrics.
+//
+//	metrics, err := prommetrics.New(prometheus.DefaultRegisterer, prommetrics.Options{})
+//	previderClient, err := client.New(&client.ClientOptions{Token: token, Metrics: metrics})
+package prommetrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/previder/previder-go-sdk/client"
+)
+
+const DefaultNamespace = "previder_api"
+
+type Options struct {
+	// Namespace prefixes all metric names, defaults to DefaultNamespace
+	Namespace string
+	// Buckets of the request duration histogram, defaults to prometheus.DefBuckets
+	Buckets []float64
+	// ConstLabels are added to all metrics, e.g. to distinguish multiple clients
+	ConstLabels prometheus.Labels
+}
+
+// Metrics implements client.Metrics with the following metrics, labelled by
+// service, operation and status class (2xx, 4xx, 5xx or error):
+//
+//	previder_api_requests_total
+//	previder_api_request_duration_seconds
+//	previder_api_request_retries_total
+//	previder_api_requests_in_flight
+type Metrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	retries  *prometheus.CounterVec
+	inFlight *prometheus.GaugeVec
+}
+
+var _ client.Metrics = (*Metrics)(nil)
+
+// New creates the metrics and registers them with registerer
+func New(registerer prometheus.Registerer, options Options) (*Metrics, error) {
+	if options.Namespace == "" {
+		options.Namespace = DefaultNamespace
+	}
+	if options.Buckets == nil {
+		options.Buckets = prometheus.DefBuckets
+	}
+
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   options.Namespace,
+			Name:        "requests_total",
+			Help:        "Number of Previder API calls.",
+			ConstLabels: options.ConstLabels,
+		}, []string{"service", "operation", "status_class"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   options.Namespace,
+			Name:        "request_duration_seconds",
+			Help:        "Duration of Previder API calls, including retries.",
+			Buckets:     options.Buckets,
+			ConstLabels: options.ConstLabels,
+		}, []string{"service", "operation", "status_class"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   options.Namespace,
+			Name:        "request_retries_total",
+			Help:        "Number of retried Previder API calls.",
+			ConstLabels: options.ConstLabels,
+		}, []string{"service", "operation"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace:   options.Namespace,
+			Name:        "requests_in_flight",
+			Help:        "Number of Previder API calls in progress.",
+			ConstLabels: options.ConstLabels,
+		}, []string{"service", "operation"}),
+	}
+
+	for _, collector := range []prometheus.Collector{m.requests, m.duration, m.retries, m.inFlight} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (m *Metrics) RequestStarted(info client.RequestInfo) {
+	m.inFlight.WithLabelValues(info.Service, info.Operation).Inc()
+}
+
+func (m *Metrics) RequestRetried(info client.RequestInfo) {
+	m.retries.WithLabelValues(info.Service, info.Operation).Inc()
+}
+
+func (m *Metrics) RequestFinished(info client.RequestInfo, statusCode int, duration time.Duration, err error) {
+	statusClass := client.StatusClass(statusCode, err)
+	m.inFlight.WithLabelValues(info.Service, info.Operation).Dec()
+	m.requests.WithLabelValues(info.Service, info.Operation, statusClass).Inc()
+	m.duration.WithLabelValues(info.Service, info.Operation, statusClass).Observe(duration.Seconds())
+}
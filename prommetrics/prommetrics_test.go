@@ -0,0 +1,134 @@
+package prommetrics_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/previder/previder-go-sdk/client"
+	"github.com/previder/previder-go-sdk/previdertest"
+	"github.com/previder/previder-go-sdk/prommetrics"
+)
+
+const (
+	service   = "iaas"
+	operation = "GET virtualmachine"
+)
+
+func TestMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics, err := prommetrics.New(registry, prommetrics.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := previdertest.NewServer()
+	t.Cleanup(server.Close)
+	options := server.ClientOptions()
+	options.Metrics = metrics
+	options.RetryPolicy = &client.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	previderClient, err := client.New(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := func(statusClass string) float64 {
+		return value(t, registry, "previder_api_requests_total", statusClass)
+	}
+	inFlight := func() float64 {
+		return value(t, registry, "previder_api_requests_in_flight", "")
+	}
+	retries := func() float64 {
+		return value(t, registry, "previder_api_request_retries_total", "")
+	}
+
+	// success
+	if _, err := previderClient.VirtualServer.Page(client.PageRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if requests("2xx") != 1 || inFlight() != 0 || retries() != 0 {
+		t.Errorf("expected 1 successful call, got %v requests, %v in flight, %v retries", requests("2xx"), inFlight(), retries())
+	}
+
+	// error, client errors are not retried
+	server.FailNext(http.MethodGet, "v2/iaas/virtualmachine", http.StatusNotFound)
+	if _, err := previderClient.VirtualServer.Page(client.PageRequest{}); err == nil {
+		t.Fatal("expected the call to fail")
+	}
+	if requests("4xx") != 1 || inFlight() != 0 || retries() != 0 {
+		t.Errorf("expected 1 failed call, got %v requests, %v in flight, %v retries", requests("4xx"), inFlight(), retries())
+	}
+
+	// retry, one call that succeeds on the second attempt
+	server.FailNext(http.MethodGet, "v2/iaas/virtualmachine", http.StatusServiceUnavailable)
+	if _, err := previderClient.VirtualServer.Page(client.PageRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if requests("2xx") != 2 || requests("5xx") != 0 || inFlight() != 0 || retries() != 1 {
+		t.Errorf("expected 1 retried call, got %v requests, %v in flight, %v retries", requests("2xx"), inFlight(), retries())
+	}
+
+	// a duration is observed for every call, labelled like the request count
+	if count := testutil.CollectAndCount(registry, "previder_api_request_duration_seconds"); count != 2 {
+		t.Errorf("expected a histogram for the 2xx and 4xx calls, got %d", count)
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != "previder_api_request_duration_seconds" {
+			continue
+		}
+		samples := uint64(0)
+		for _, metric := range family.GetMetric() {
+			samples += metric.GetHistogram().GetSampleCount()
+		}
+		if samples != 3 {
+			t.Errorf("expected 3 observed durations, got %d", samples)
+		}
+	}
+}
+
+// value returns the counter or gauge of the operation with the status class, 0 if absent
+func value(t *testing.T, registry *prometheus.Registry, name string, statusClass string) float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["service"] != service || labels["operation"] != operation || labels["status_class"] != statusClass {
+				continue
+			}
+			if metric.GetCounter() != nil {
+				return metric.GetCounter().GetValue()
+			}
+			return metric.GetGauge().GetValue()
+		}
+	}
+	return 0
+}
+
+func TestNewRegistersOnce(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	if _, err := prommetrics.New(registry, prommetrics.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := prommetrics.New(registry, prommetrics.Options{}); err == nil {
+		t.Error("expected registering the metrics twice to fail")
+	}
+	if _, err := prommetrics.New(registry, prommetrics.Options{Namespace: "other"}); err != nil {
+		t.Errorf("expected metrics with another namespace to register, got %v", err)
+	}
+}